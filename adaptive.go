@@ -0,0 +1,73 @@
+package scientist
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// adaptiveWindow is how many runs are counted before the percentage changes.
+const adaptiveWindow = 100
+
+type adaptiveConfig struct {
+	min          float64
+	max          float64
+	mismatchRate float64
+}
+
+// adaptiveState raises the percentage a step at a time while mismatches are
+// rare, and halves it when they aren't.
+type adaptiveState struct {
+	percent    uint64 // float64 bits
+	mu         sync.Mutex
+	runs       int
+	mismatches int
+}
+
+func (e *Experiment) AdaptivePercent(min, max, mismatchRate float64) {
+	if !e.configurable("AdaptivePercent") {
+		return
+	}
+
+	// the experiment would never run again to raise a zero percentage.
+	if min <= 0 || max < min {
+		e.invalid(fmt.Errorf("AdaptivePercent needs 0 < min <= max, got %v and %v", min, max))
+		return
+	}
+
+	e.adaptive = &adaptiveConfig{min: min, max: max, mismatchRate: mismatchRate}
+}
+
+func (s *adaptiveState) current(c *adaptiveConfig) float64 {
+	bits := atomic.LoadUint64(&s.percent)
+	if bits == 0 {
+		return c.min
+	}
+	return math.Float64frombits(bits)
+}
+
+func (s *adaptiveState) record(c *adaptiveConfig, mismatched bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.runs++
+	if mismatched {
+		s.mismatches++
+	}
+
+	if s.runs < adaptiveWindow {
+		return
+	}
+
+	percent := s.current(c)
+	if float64(s.mismatches)/float64(s.runs) > c.mismatchRate {
+		percent = math.Max(c.min, percent/2)
+	} else {
+		percent = math.Min(c.max, percent+(c.max-c.min)/10)
+	}
+
+	s.runs = 0
+	s.mismatches = 0
+	atomic.StoreUint64(&s.percent, math.Float64bits(percent))
+}
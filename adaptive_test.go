@@ -0,0 +1,81 @@
+package scientist
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAdaptiveState(t *testing.T) {
+	c := &adaptiveConfig{min: 1, max: 21, mismatchRate: 0.05}
+	s := &adaptiveState{}
+
+	if p := s.current(c); p != 1 {
+		t.Errorf("Unexpected starting percent: %v", p)
+	}
+
+	for i := 0; i < adaptiveWindow*3; i++ {
+		s.record(c, false)
+	}
+
+	if p := s.current(c); p != 7 {
+		t.Errorf("Unexpected percent after matches: %v", p)
+	}
+
+	for i := 0; i < adaptiveWindow; i++ {
+		s.record(c, i%10 == 0)
+	}
+
+	if p := s.current(c); p != 3.5 {
+		t.Errorf("Unexpected percent after mismatches: %v", p)
+	}
+
+	for i := 0; i < adaptiveWindow*20; i++ {
+		s.record(c, false)
+	}
+
+	if p := s.current(c); p != 21 {
+		t.Errorf("Unexpected percent after ramping up: %v", p)
+	}
+
+	for i := 0; i < adaptiveWindow*20; i++ {
+		s.record(c, true)
+	}
+
+	if p := s.current(c); p != 1 {
+		t.Errorf("Unexpected percent after ramping down: %v", p)
+	}
+}
+
+func TestAdaptivePercent(t *testing.T) {
+	runs := 0
+
+	e := New(uniqueName("adaptive"))
+	e.AdaptivePercent(0.001, 100, 0.01)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		runs++
+		return 1, nil
+	})
+
+	e.Run()
+	if runs != 0 {
+		t.Errorf("expected experiment to start at the minimum percent")
+	}
+
+	for i := 0; i < adaptiveWindow*10; i++ {
+		e.state.adaptive.record(e.adaptive, false)
+	}
+
+	e.Run()
+	if runs != 1 {
+		t.Errorf("expected experiment to run after ramping up")
+	}
+
+	invalid := New("adaptive-invalid")
+	invalid.AdaptivePercent(0, 100, 0.01)
+	if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), "AdaptivePercent") {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
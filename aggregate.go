@@ -0,0 +1,250 @@
+package scientist
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// aggregateSamples is how many runtimes are kept per behavior to estimate
+	// percentiles.
+	aggregateSamples = 1000
+
+	// aggregateMismatches is how many distinct mismatches are counted per
+	// experiment, so unique diffs can't grow the aggregator forever.
+	aggregateMismatches = 1000
+)
+
+// Aggregator collects results across many runs. Use its Publish method as an
+// experiment's publisher, alone or alongside another publisher.
+type Aggregator struct {
+	maxExamples int
+
+	mu          sync.Mutex
+	experiments map[string]*aggregate
+}
+
+type Summary struct {
+	Experiment   string
+	Control      string
+	Runs         int
+	Matched      int
+	Mismatched   int
+	Ignored      int
+	Skipped      int
+	MatchRate    float64
+	MismatchRate float64
+	Behaviors    map[string]RuntimeSummary
+
+	// Variants counts the runs of each behavior from RunVariant. They're
+	// included in Runs, but nothing was compared, so they aren't Matched.
+	Variants map[string]int
+
+	// Mismatches are the most common mismatches, grouped by candidate and
+	// diff.
+	Mismatches []MismatchExample
+}
+
+type RuntimeSummary struct {
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+}
+
+type MismatchExample struct {
+	Candidate string
+	Diff      string
+	Count     int
+	Result    Result
+}
+
+type aggregate struct {
+	control    string
+	runs       int
+	matched    int
+	mismatched int
+	ignored    int
+	skipped    int
+	variants   map[string]int
+	runtimes   map[string]*reservoir
+	mismatches map[mismatchKey]*MismatchExample
+}
+
+type mismatchKey struct {
+	candidate string
+	diff      string
+}
+
+// reservoir keeps a uniform sample of runtimes.
+type reservoir struct {
+	count   int
+	samples []time.Duration
+}
+
+func NewAggregator(maxExamples int) *Aggregator {
+	return &Aggregator{
+		maxExamples: maxExamples,
+		experiments: make(map[string]*aggregate),
+	}
+}
+
+func (a *Aggregator) Publish(r Result) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	agg, ok := a.experiments[r.Experiment.Name]
+	if !ok {
+		agg = &aggregate{
+			runtimes:   make(map[string]*reservoir),
+			mismatches: make(map[mismatchKey]*MismatchExample),
+		}
+		a.experiments[r.Experiment.Name] = agg
+	}
+
+	agg.add(r)
+	return nil
+}
+
+func (a *Aggregator) Summary() []Summary {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	summaries := make([]Summary, 0, len(a.experiments))
+	for name, agg := range a.experiments {
+		summaries = append(summaries, agg.summary(name, a.maxExamples))
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Experiment < summaries[j].Experiment
+	})
+	return summaries
+}
+
+func (a *aggregate) add(r Result) {
+	if r.SkipReason != "" {
+		a.skipped++
+		return
+	}
+
+	a.runs++
+	if r.Control != nil {
+		a.control = r.Control.Name
+	}
+
+	switch {
+	case r.Variant != "":
+		if a.variants == nil {
+			a.variants = make(map[string]int)
+		}
+		a.variants[r.Variant]++
+	case r.IsMismatched():
+		a.mismatched++
+	case r.IsIgnored():
+		a.ignored++
+	default:
+		a.matched++
+	}
+
+	for _, o := range r.Observations {
+		res, ok := a.runtimes[o.Name]
+		if !ok {
+			res = &reservoir{}
+			a.runtimes[o.Name] = res
+		}
+		res.add(o.Runtime)
+	}
+
+	for _, o := range r.Mismatched {
+		key := mismatchKey{candidate: o.Name, diff: o.Diff}
+		if ex, ok := a.mismatches[key]; ok {
+			ex.Count++
+		} else if len(a.mismatches) < aggregateMismatches {
+			a.mismatches[key] = &MismatchExample{Candidate: o.Name, Diff: o.Diff, Count: 1, Result: r}
+		}
+	}
+}
+
+func (a *aggregate) summary(name string, maxExamples int) Summary {
+	s := Summary{
+		Experiment:   name,
+		Control:      a.control,
+		Runs:         a.runs,
+		Matched:      a.matched,
+		Mismatched:   a.mismatched,
+		Ignored:      a.ignored,
+		Skipped:      a.skipped,
+		MatchRate:    rate(int64(a.matched), int64(a.runs)),
+		MismatchRate: rate(int64(a.mismatched), int64(a.runs)),
+		Behaviors:    make(map[string]RuntimeSummary, len(a.runtimes)),
+	}
+
+	if len(a.variants) > 0 {
+		s.Variants = make(map[string]int, len(a.variants))
+		for variant, n := range a.variants {
+			s.Variants[variant] = n
+		}
+	}
+
+	for bname, res := range a.runtimes {
+		s.Behaviors[bname] = res.summary()
+	}
+
+	for _, ex := range a.mismatches {
+		s.Mismatches = append(s.Mismatches, *ex)
+	}
+
+	sort.Slice(s.Mismatches, func(i, j int) bool {
+		if s.Mismatches[i].Count != s.Mismatches[j].Count {
+			return s.Mismatches[i].Count > s.Mismatches[j].Count
+		}
+		return s.Mismatches[i].Candidate < s.Mismatches[j].Candidate
+	})
+
+	if len(s.Mismatches) > maxExamples {
+		s.Mismatches = s.Mismatches[:maxExamples]
+	}
+
+	return s
+}
+
+func (r *reservoir) add(d time.Duration) {
+	r.count++
+	if len(r.samples) < aggregateSamples {
+		r.samples = append(r.samples, d)
+		return
+	}
+
+	if i := rand.Intn(r.count); i < aggregateSamples {
+		r.samples[i] = d
+	}
+}
+
+func (r *reservoir) summary() RuntimeSummary {
+	sorted := append([]time.Duration(nil), r.samples...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return RuntimeSummary{
+		Count: r.count,
+		P50:   percentile(sorted, 0.50),
+		P95:   percentile(sorted, 0.95),
+		P99:   percentile(sorted, 0.99),
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	i := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
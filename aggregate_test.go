@@ -0,0 +1,103 @@
+package scientist
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAggregator(t *testing.T) {
+	agg := NewAggregator(1)
+
+	e := New("aggregate")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	value := 1
+	e.Try(func() (interface{}, error) {
+		return value, nil
+	})
+	e.Publish(agg.Publish)
+
+	for i := 0; i < 10; i++ {
+		switch {
+		case i < 6:
+			value = 1
+		case i < 9:
+			value = 2
+		default:
+			value = 3
+		}
+		e.Run()
+	}
+
+	summaries := agg.Summary()
+	if len(summaries) != 1 {
+		t.Fatalf("Unexpected summaries: %v", summaries)
+	}
+
+	s := summaries[0]
+	if s.Experiment != "aggregate" || s.Control != "control" || s.Runs != 10 || s.Matched != 6 || s.Mismatched != 4 {
+		t.Errorf("Unexpected summary: %+v", s)
+	}
+
+	if s.MatchRate != 0.6 || s.MismatchRate != 0.4 {
+		t.Errorf("Unexpected rates: %v, %v", s.MatchRate, s.MismatchRate)
+	}
+
+	for _, name := range []string{"control", "candidate"} {
+		if b := s.Behaviors[name]; b.Count != 10 || b.P99 < b.P50 {
+			t.Errorf("Unexpected %s runtimes: %+v", name, b)
+		}
+	}
+
+	if len(s.Mismatches) != 1 || s.Mismatches[0].Count != 3 || s.Mismatches[0].Result.Candidates[0].Value != 2 {
+		t.Errorf("Unexpected mismatches: %+v", s.Mismatches)
+	}
+}
+
+func TestAggregatorVariants(t *testing.T) {
+	agg := NewAggregator(1)
+
+	e := New("aggregate-variants")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Publish(agg.Publish)
+
+	for i := 0; i < 20; i++ {
+		e.RunVariant(fmt.Sprint(i))
+	}
+
+	s := agg.Summary()[0]
+	if s.Runs != 20 || s.Matched != 0 || s.Mismatched != 0 || s.Variants["control"]+s.Variants["candidate"] != 20 {
+		t.Errorf("Unexpected summary: %+v", s)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 100)
+	for i := range sorted {
+		sorted[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := map[float64]time.Duration{
+		0.50: 50 * time.Millisecond,
+		0.95: 95 * time.Millisecond,
+		0.99: 99 * time.Millisecond,
+	}
+
+	for p, expected := range tests {
+		if actual := percentile(sorted, p); actual != expected {
+			t.Errorf("Unexpected p%v: %v", p*100, actual)
+		}
+	}
+
+	if actual := percentile(nil, 0.5); actual != 0 {
+		t.Errorf("Unexpected empty percentile: %v", actual)
+	}
+}
@@ -0,0 +1,47 @@
+package scientist
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// memStats is read into in place, so reading it doesn't count toward the
+// allocations it measures. Concurrent behaviors share it, so it's read under a
+// lock.
+var memStats struct {
+	sync.Mutex
+	runtime.MemStats
+}
+
+func (e *Experiment) MeasureAllocations() {
+	if !e.configurable("MeasureAllocations") {
+		return
+	}
+
+	e.measureAllocs = true
+}
+
+// readAllocs returns the bytes and objects allocated by the whole process so
+// far. runtime.ReadMemStats stops the world, but it flushes every P's cached
+// counts, so small allocations are counted as soon as they happen.
+func readAllocs() (bytes, objects uint64) {
+	memStats.Lock()
+	defer memStats.Unlock()
+
+	runtime.ReadMemStats(&memStats.MemStats)
+	return memStats.TotalAlloc, memStats.Mallocs
+}
+
+// measuredAllocs records the allocations of the behavior's call in o. It wraps
+// the behavior itself, so profiling, tracing, and middleware don't count.
+func measuredAllocs(o *Observation, b behaviorFunc) behaviorFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		bytes, objects := readAllocs()
+		v, err := b(ctx)
+		afterBytes, afterObjects := readAllocs()
+		o.AllocBytes = afterBytes - bytes
+		o.Allocs = afterObjects - objects
+		return v, err
+	}
+}
@@ -0,0 +1,76 @@
+package scientist
+
+import "testing"
+
+var allocSink []byte
+
+func TestMeasureAllocations(t *testing.T) {
+	e := New("allocs")
+	e.MeasureAllocations()
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		allocSink = make([]byte, 1<<20)
+		return 1, nil
+	})
+
+	r := Run(e, "control")
+	if r.Control.AllocBytes >= 1<<20 {
+		t.Errorf("Unexpected control allocations: %d bytes", r.Control.AllocBytes)
+	}
+
+	candidate := r.Candidates[0]
+	if candidate.AllocBytes < 1<<20 || candidate.Allocs == 0 {
+		t.Errorf("Unexpected candidate allocations: %d bytes, %d objects", candidate.AllocBytes, candidate.Allocs)
+	}
+
+	if p := candidate.Payload(); p.AllocBytes != candidate.AllocBytes {
+		t.Errorf("Unexpected payload allocations: %d bytes", p.AllocBytes)
+	}
+}
+
+func TestMeasureAllocationsDisabled(t *testing.T) {
+	e := New("allocs")
+	e.Use(func() (interface{}, error) {
+		allocSink = make([]byte, 1<<20)
+		return 1, nil
+	})
+
+	if r := Run(e, "control"); r.Control.AllocBytes != 0 {
+		t.Errorf("Unexpected control allocations: %d bytes", r.Control.AllocBytes)
+	}
+}
+
+func TestMeasureAllocationsNone(t *testing.T) {
+	e := New("allocs-none")
+	e.MeasureAllocations()
+	e.Use(func() (interface{}, error) {
+		return nil, nil
+	})
+
+	for i := 0; i < 100; i++ {
+		if r := Run(e, "control"); r.Control.AllocBytes != 0 || r.Control.Allocs != 0 {
+			t.Fatalf("Expected a behavior that doesn't allocate to report 0 allocations: %d bytes, %d objects", r.Control.AllocBytes, r.Control.Allocs)
+		}
+	}
+}
+
+var smallSink [10]*[16]byte
+
+func TestMeasureAllocationsSmall(t *testing.T) {
+	e := New("allocs-small")
+	e.MeasureAllocations()
+	e.Use(func() (interface{}, error) {
+		for i := range smallSink {
+			smallSink[i] = new([16]byte)
+		}
+		return nil, nil
+	})
+
+	for i := 0; i < 10; i++ {
+		if r := Run(e, "control"); r.Control.Allocs != 10 || r.Control.AllocBytes != 160 {
+			t.Fatalf("Expected 10 allocations of 16 bytes: %d bytes, %d objects", r.Control.AllocBytes, r.Control.Allocs)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package scientist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// batchExamples is how many mismatches RunBatch keeps for each experiment.
+const batchExamples = 10
+
+// ErrAlreadyRan is returned by RunBatch when build returns an experiment that
+// already ran, since its results can't be collected.
+var ErrAlreadyRan = errors.New("[scientist] experiment already ran")
+
+// RunBatch runs a new experiment from build for each input, one at a time,
+// and summarizes the results. Results are collected with AfterRun, so every
+// run is counted even if the experiment samples matched results. Each run
+// waits for its result, even with EnableAsync. If build returns an experiment
+// that already ran, RunBatch stops and returns ErrAlreadyRan.
+func RunBatch[In any](inputs []In, build func(input In) *Experiment) ([]Summary, error) {
+	agg := NewAggregator(batchExamples)
+	for _, input := range inputs {
+		r, ok, err := runConcluded(context.Background(), build(input))
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			agg.Publish(r)
+		}
+	}
+
+	return agg.Summary(), nil
+}
+
+// RunStream runs a new experiment from build for each input received, with
+// up to concurrency experiments running at once. Each result is sent on the
+// returned channel, which is closed once inputs is closed and the last
+// experiment finishes. Runs that skip their candidates don't send a result,
+// and neither do experiments that already ran, since AfterRun can't be added
+// to them. That's reported to their ReportErrors callback.
+//
+// Canceling ctx stops reading inputs and cancels running experiments.
+func RunStream[In any](ctx context.Context, inputs <-chan In, concurrency int, build func(input In) *Experiment) <-chan Result {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make(chan Result)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case input, ok := <-inputs:
+					if !ok {
+						return
+					}
+					runStreamed(ctx, build(input), results)
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// runStreamed sends each experiment's result. Experiments that already ran
+// can't send one, so they're skipped.
+func runStreamed(ctx context.Context, e *Experiment, results chan<- Result) {
+	r, ok, err := runConcluded(ctx, e)
+	if err != nil || !ok {
+		return
+	}
+
+	select {
+	case results <- r:
+	case <-ctx.Done():
+	}
+}
+
+// runConcluded runs the experiment, and returns the result AfterRun sees once
+// its candidates are compared. That comes from the background with
+// EnableAsync, so it's passed over a channel. It returns false if the
+// candidates didn't run, or ctx is done first.
+func runConcluded(ctx context.Context, e *Experiment) (Result, bool, error) {
+	concluded := make(chan Result, 1)
+	added := e.afterRun(func(r Result) error {
+		concluded <- r
+		return nil
+	})
+	if !added {
+		return Result{}, false, fmt.Errorf("%w: %q", ErrAlreadyRan, e.Name)
+	}
+
+	v, r, _ := e.conduct(controlBehavior, runOptions{ctx: ctx})
+	if r.Control == nil || r.SkipReason != "" {
+		return Result{}, false, nil
+	}
+
+	// streams are only compared once the control is read.
+	if rc, ok := v.(io.ReadCloser); ok && e.streams {
+		io.Copy(io.Discard, rc)
+		rc.Close()
+	}
+
+	select {
+	case result := <-concluded:
+		return result, true, nil
+	case <-ctx.Done():
+		return Result{}, false, nil
+	}
+}
@@ -0,0 +1,218 @@
+package scientist
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunBatch(t *testing.T) {
+	inputs := []int{1, 2, 3, 4}
+	summaries, err := RunBatch(inputs, func(n int) *Experiment {
+		e := New("batch")
+		e.Use(func() (interface{}, error) {
+			return n * 2, nil
+		})
+		e.Try(func() (interface{}, error) {
+			if n == 3 {
+				return n * n, nil
+			}
+			return n + n, nil
+		})
+		e.SampleMatched(0)
+		return e
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summaries) != 1 {
+		t.Fatalf("Unexpected summaries: %+v", summaries)
+	}
+
+	s := summaries[0]
+	if s.Runs != 4 || s.Matched != 3 || s.Mismatched != 1 {
+		t.Errorf("Unexpected summary: %+v", s)
+	}
+	if len(s.Mismatches) != 1 || s.Mismatches[0].Result.Control.Value != 6 {
+		t.Errorf("Unexpected mismatches: %+v", s.Mismatches)
+	}
+}
+
+func TestRunBatchAsync(t *testing.T) {
+	summaries, err := RunBatch([]int{1, 2, 3}, func(n int) *Experiment {
+		e := New("batch-async")
+		e.EnableAsync()
+		e.Use(func() (interface{}, error) {
+			return n, nil
+		})
+		e.Try(func() (interface{}, error) {
+			time.Sleep(time.Millisecond)
+			return n, nil
+		})
+		return e
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(summaries) != 1 || summaries[0].Runs != 3 || summaries[0].Matched != 3 {
+		t.Errorf("Expected every async run to be counted: %+v", summaries)
+	}
+}
+
+func TestRunBatchAlreadyRan(t *testing.T) {
+	e := New("batch-already-ran")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.ReportErrors(func(errs ...ResultError) {})
+	e.Run()
+
+	_, err := RunBatch([]int{1}, func(n int) *Experiment {
+		return e
+	})
+	if !errors.Is(err, ErrAlreadyRan) {
+		t.Errorf("Expected ErrAlreadyRan, got %v", err)
+	}
+
+	inputs := make(chan int, 1)
+	inputs <- 1
+	close(inputs)
+
+	results := RunStream(context.Background(), inputs, 1, func(n int) *Experiment {
+		return e
+	})
+
+	select {
+	case r, ok := <-results:
+		if ok {
+			t.Errorf("Unexpected result from an experiment that already ran: %+v", r)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected an experiment that already ran not to block the stream")
+	}
+}
+
+func TestRunStream(t *testing.T) {
+	inputs := make(chan int)
+	go func() {
+		for i := 0; i < 20; i++ {
+			inputs <- i
+		}
+		close(inputs)
+	}()
+
+	var running, maxRunning int32
+	results := RunStream(context.Background(), inputs, 4, func(n int) *Experiment {
+		e := New("stream")
+		e.Use(func() (interface{}, error) {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+					break
+				}
+			}
+
+			time.Sleep(time.Millisecond)
+			return n, nil
+		})
+		e.Try(func() (interface{}, error) {
+			if n%5 == 0 {
+				return -n, nil
+			}
+			return n, nil
+		})
+		return e
+	})
+
+	seen := make(map[interface{}]bool)
+	mismatched := 0
+	for r := range results {
+		seen[r.Control.Value] = true
+		if r.IsMismatched() {
+			mismatched++
+		}
+	}
+
+	if len(seen) != 20 {
+		t.Errorf("Expected 20 results, got %d", len(seen))
+	}
+	if mismatched != 3 {
+		t.Errorf("Expected 3 mismatches, got %d", mismatched)
+	}
+	if max := atomic.LoadInt32(&maxRunning); max > 4 || max < 1 {
+		t.Errorf("Unexpected concurrency: %d", max)
+	}
+}
+
+func TestRunStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	inputs := make(chan int)
+
+	results := RunStream(ctx, inputs, 2, func(n int) *Experiment {
+		e := New("stream-cancel")
+		e.Use(func() (interface{}, error) {
+			return n, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return n, nil
+		})
+		return e
+	})
+
+	inputs <- 1
+	<-results
+	cancel()
+
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Errorf("Unexpected result after canceling")
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected results to close after canceling")
+	}
+}
+
+func TestRunStreamAsync(t *testing.T) {
+	inputs := make(chan int)
+	go func() {
+		for i := 0; i < 20; i++ {
+			inputs <- i
+		}
+		close(inputs)
+	}()
+
+	results := RunStream(context.Background(), inputs, 4, func(n int) *Experiment {
+		e := New("stream-async")
+		e.EnableAsync()
+		e.Use(func() (interface{}, error) {
+			return n, nil
+		})
+		e.Try(func() (interface{}, error) {
+			time.Sleep(time.Millisecond)
+			return n, nil
+		})
+		return e
+	})
+
+	seen := make(map[interface{}]bool)
+	for r := range results {
+		if len(r.Candidates) != 1 {
+			t.Errorf("Expected the concluded result, got %+v", r)
+		}
+		seen[r.Control.Value] = true
+	}
+
+	if len(seen) != 20 {
+		t.Errorf("Expected 20 results, got %d", len(seen))
+	}
+}
@@ -0,0 +1,72 @@
+package scientist
+
+import "testing"
+
+func BenchmarkRunSkipped(b *testing.B) {
+	e := New("bench-skipped")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.RunPercent(0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Run()
+	}
+}
+
+func BenchmarkRunControl(b *testing.B) {
+	e := New("bench-control")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Run()
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	e := New("bench-run")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Publish(func(r Result) error {
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Run()
+	}
+}
+
+func BenchmarkRunConcurrent(b *testing.B) {
+	e := New("bench-run-concurrent")
+	e.EnableConcurrency(0)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Publish(func(r Result) error {
+		return nil
+	})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Run()
+	}
+}
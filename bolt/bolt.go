@@ -0,0 +1,211 @@
+// Package bolt stores mismatched scientist results in a bbolt database, so
+// they survive restarts and can be triaged later. Mismatches are kept in a
+// bucket per experiment, ordered by the time they were published.
+package bolt
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"scientist"
+	"sort"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+var mismatchesBucket = []byte("mismatches")
+
+type Store struct {
+	db *bbolt.DB
+}
+
+// Mismatch is a stored mismatched result.
+type Mismatch struct {
+	ID     uint64
+	Time   time.Time
+	Result scientist.ResultPayload
+}
+
+// Query filters stored mismatches. Zero values match everything.
+type Query struct {
+	Experiment string
+
+	// Candidate matches results where the named candidate mismatched.
+	Candidate string
+
+	// Since and Until limit results to the half-open range [Since, Until).
+	Since time.Time
+	Until time.Time
+
+	// Limit is the maximum number of mismatches returned.
+	Limit int
+}
+
+// Open opens or creates the database at path. It waits up to a second for
+// other processes to release the file.
+func Open(path string) (*Store, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(mismatchesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Publish stores mismatched results. Other results are ignored.
+func (s *Store) Publish(r scientist.Result) error {
+	if !r.IsMismatched() {
+		return nil
+	}
+
+	value, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.Bucket(mismatchesBucket).CreateBucketIfNotExists([]byte(r.Experiment.Name))
+		if err != nil {
+			return err
+		}
+
+		id, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		return b.Put(key(now, id), value)
+	})
+}
+
+// Find returns the stored mismatches matching the query, oldest first.
+func (s *Store) Find(q Query) ([]Mismatch, error) {
+	var found []Mismatch
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		root := tx.Bucket(mismatchesBucket)
+		names := []string{q.Experiment}
+		if q.Experiment == "" {
+			names = experiments(root)
+		}
+
+		for _, name := range names {
+			b := root.Bucket([]byte(name))
+			if b == nil {
+				continue
+			}
+
+			c := b.Cursor()
+			for k, v := c.Seek(key(q.Since, 0)); k != nil; k, v = c.Next() {
+				m := Mismatch{ID: binary.BigEndian.Uint64(k[8:]), Time: keyTime(k)}
+				if !q.Until.IsZero() && !m.Time.Before(q.Until) {
+					break
+				}
+
+				if err := json.Unmarshal(v, &m.Result); err != nil {
+					return err
+				}
+				if q.Candidate == "" || contains(m.Result.MismatchedCandidates, q.Candidate) {
+					found = append(found, m)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Experiments are scanned one at a time, so merge them back in order.
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].Time.Before(found[j].Time)
+	})
+
+	if q.Limit > 0 && len(found) > q.Limit {
+		found = found[:q.Limit]
+	}
+	return found, nil
+}
+
+// Experiments returns the names of experiments with stored mismatches.
+func (s *Store) Experiments() ([]string, error) {
+	var names []string
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		names = experiments(tx.Bucket(mismatchesBucket))
+		return nil
+	})
+	return names, err
+}
+
+// Prune deletes mismatches published before the given time, and returns the
+// number deleted.
+func (s *Store) Prune(before time.Time) (int, error) {
+	deleted := 0
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		root := tx.Bucket(mismatchesBucket)
+		for _, name := range experiments(root) {
+			b := root.Bucket([]byte(name))
+
+			// Deleting while iterating moves the cursor, so collect the keys
+			// first.
+			var keys [][]byte
+			c := b.Cursor()
+			for k, _ := c.First(); k != nil && keyTime(k).Before(before); k, _ = c.Next() {
+				keys = append(keys, k)
+			}
+
+			for _, k := range keys {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			deleted += len(keys)
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+func experiments(root *bbolt.Bucket) []string {
+	var names []string
+	root.ForEachBucket(func(k []byte) error {
+		names = append(names, string(k))
+		return nil
+	})
+	return names
+}
+
+// key sorts mismatches by time, then by the experiment bucket's sequence.
+func key(t time.Time, id uint64) []byte {
+	k := make([]byte, 16)
+	if !t.IsZero() {
+		binary.BigEndian.PutUint64(k, uint64(t.UnixNano()))
+	}
+	binary.BigEndian.PutUint64(k[8:], id)
+	return k
+}
+
+func keyTime(k []byte) time.Time {
+	return time.Unix(0, int64(binary.BigEndian.Uint64(k)))
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
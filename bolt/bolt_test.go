@@ -0,0 +1,187 @@
+package bolt
+
+import (
+	"path/filepath"
+	"scientist"
+	"testing"
+	"time"
+)
+
+func TestPublishAndFind(t *testing.T) {
+	s := openStore(t)
+
+	publish(t, s, "widgets", 1, 2)
+	publish(t, s, "widgets", 1, 1)
+	mid := time.Now()
+	publish(t, s, "gadgets", 1, 3)
+	publish(t, s, "widgets", 1, 4)
+
+	all, err := s.Find(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 mismatches, got %d", len(all))
+	}
+
+	expected := []string{"widgets", "gadgets", "widgets"}
+	for i, m := range all {
+		if m.Result.Experiment != expected[i] {
+			t.Errorf("Unexpected mismatch %d: %q", i, m.Result.Experiment)
+		}
+		if i > 0 && m.Time.Before(all[i-1].Time) {
+			t.Errorf("Mismatches out of order: %v", all)
+		}
+	}
+
+	widgets, err := s.Find(Query{Experiment: "widgets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(widgets) != 2 {
+		t.Fatalf("Expected 2 widgets mismatches, got %d", len(widgets))
+	}
+	if v := widgets[0].Result.Candidates[0].Value; v != float64(2) {
+		t.Errorf("Unexpected candidate value: %v", v)
+	}
+
+	recent, err := s.Find(Query{Experiment: "widgets", Since: mid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 1 || recent[0].Result.Candidates[0].Value != float64(4) {
+		t.Errorf("Unexpected recent mismatches: %+v", recent)
+	}
+
+	older, err := s.Find(Query{Until: mid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(older) != 1 || older[0].Result.Experiment != "widgets" {
+		t.Errorf("Unexpected older mismatches: %+v", older)
+	}
+
+	limited, err := s.Find(Query{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(limited) != 2 || limited[1].Result.Experiment != "gadgets" {
+		t.Errorf("Unexpected limited mismatches: %+v", limited)
+	}
+
+	names, err := s.Experiments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 2 || names[0] != "gadgets" || names[1] != "widgets" {
+		t.Errorf("Unexpected experiments: %v", names)
+	}
+}
+
+func TestFindCandidate(t *testing.T) {
+	s := openStore(t)
+
+	e := scientist.New("bolt-candidates")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Behavior("correct", func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Publish(s.Publish)
+	e.Run()
+
+	found, err := s.Find(Query{Candidate: "candidate"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 {
+		t.Errorf("Expected 1 candidate mismatch, got %d", len(found))
+	}
+
+	found, err = s.Find(Query{Candidate: "correct"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 0 {
+		t.Errorf("Expected no correct mismatches, got %d", len(found))
+	}
+}
+
+func TestPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "science.db")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publish(t, s, "widgets", 1, 2)
+	s.Close()
+
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	found, err := s.Find(Query{Experiment: "widgets"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].ID != 1 {
+		t.Errorf("Unexpected mismatches after reopening: %+v", found)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	s := openStore(t)
+
+	publish(t, s, "widgets", 1, 2)
+	publish(t, s, "gadgets", 1, 2)
+	mid := time.Now()
+	publish(t, s, "widgets", 1, 3)
+
+	deleted, err := s.Prune(mid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Errorf("Expected 2 deleted mismatches, got %d", deleted)
+	}
+
+	found, err := s.Find(Query{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Result.Candidates[0].Value != float64(3) {
+		t.Errorf("Unexpected mismatches after pruning: %+v", found)
+	}
+}
+
+func openStore(t *testing.T) *Store {
+	s, err := Open(filepath.Join(t.TempDir(), "science.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+	})
+	return s
+}
+
+func publish(t *testing.T, s *Store, name string, control, candidate int) {
+	e := scientist.New(name)
+	e.Use(func() (interface{}, error) {
+		return control, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return candidate, nil
+	})
+	e.Publish(s.Publish)
+	e.ReportErrors(func(errs ...scientist.ResultError) {
+		t.Errorf("Unexpected errors: %v", errs)
+	})
+	e.Run()
+}
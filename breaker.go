@@ -0,0 +1,120 @@
+package scientist
+
+import (
+	"sync"
+	"time"
+)
+
+const SkipTripped = "tripped"
+
+type breakerConfig struct {
+	failures int
+	window   time.Duration
+	cooldown time.Duration
+}
+
+// breaker tracks the failures of one candidate. It's closed while the
+// candidate runs normally, and opens when the candidate fails too often. Once
+// the cooldown is over, it lets a single probe run to decide whether to close
+// again.
+type breaker struct {
+	mu        sync.Mutex
+	failures  []time.Time
+	openUntil time.Time
+	probing   bool
+}
+
+func (e *Experiment) CircuitBreaker(failures int, window, cooldown time.Duration) {
+	if !e.configurable("CircuitBreaker") {
+		return
+	}
+
+	e.breaker = &breakerConfig{failures: failures, window: window, cooldown: cooldown}
+}
+
+// candidateBreaker returns nil if the experiment has no circuit breaker.
+// Breakers are shared by every experiment with the same name.
+func (e *Experiment) candidateBreaker(name string) *breaker {
+	if e.breaker == nil || e.breaker.failures <= 0 {
+		return nil
+	}
+
+	b, _ := e.state.breakers.LoadOrStore(name, &breaker{})
+	return b.(*breaker)
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return true
+	}
+
+	if now.Before(b.openUntil) || b.probing {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+func (b *breaker) record(c *breakerConfig, err error, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.probing {
+		b.probing = false
+		if err == nil {
+			b.openUntil = time.Time{}
+		} else {
+			b.openUntil = now.Add(c.cooldown)
+		}
+		return
+	}
+
+	if err == nil || !b.openUntil.IsZero() {
+		return
+	}
+
+	recent := b.failures[:0]
+	for _, t := range b.failures {
+		if now.Sub(t) < c.window {
+			recent = append(recent, t)
+		}
+	}
+	b.failures = append(recent, now)
+
+	if len(b.failures) >= c.failures {
+		b.failures = nil
+		b.openUntil = now.Add(c.cooldown)
+	}
+}
+
+// release gives up a probe that never ran, like a shed candidate.
+func (b *breaker) release() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func recordBreakers(e *Experiment, candidates []*Observation) {
+	if e.breaker == nil {
+		return
+	}
+
+	now := time.Now()
+	for _, c := range candidates {
+		b := e.candidateBreaker(c.Name)
+		if b == nil {
+			continue
+		}
+
+		switch c.SkipReason {
+		case "":
+			b.record(e.breaker, c.Err, now)
+		case SkipShed:
+			b.release()
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package scientist
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var candidateErr error
+	runs := 0
+
+	e := New("breaker")
+	e.CircuitBreaker(2, time.Minute, 20*time.Millisecond)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		runs++
+		return 1, candidateErr
+	})
+	e.Behavior("fine", func() (interface{}, error) {
+		return 1, nil
+	})
+
+	candidateErr = errors.New("broken")
+	Run(e, "control")
+	Run(e, "control")
+	if runs != 2 {
+		t.Errorf("Unexpected candidate runs: %d", runs)
+	}
+
+	r := Run(e, "control")
+	if runs != 2 {
+		t.Errorf("expected tripped candidate not to run")
+	}
+
+	assertObservationNames(t, "candidate", r.Candidates, []string{"fine"})
+	assertObservationNames(t, "skipped", r.Skipped, []string{"candidate"})
+	if reason := r.Skipped[0].SkipReason; reason != SkipTripped {
+		t.Errorf("Unexpected skip reason: %q", reason)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	candidateErr = nil
+
+	r = Run(e, "control")
+	if runs != 3 {
+		t.Errorf("expected probe to run")
+	}
+	assertObservationNames(t, "candidate", r.Candidates, []string{"candidate", "fine"})
+
+	Run(e, "control")
+	if runs != 4 {
+		t.Errorf("expected closed breaker to run candidate")
+	}
+}
+
+func TestBreakerFailedProbe(t *testing.T) {
+	c := &breakerConfig{failures: 1, window: time.Minute, cooldown: time.Minute}
+	b := &breaker{}
+	now := time.Now()
+
+	b.record(c, errors.New("broken"), now)
+	if b.allow(now) {
+		t.Errorf("expected open breaker")
+	}
+
+	later := now.Add(2 * time.Minute)
+	if !b.allow(later) {
+		t.Errorf("expected probe")
+	}
+
+	if b.allow(later) {
+		t.Errorf("expected a single probe")
+	}
+
+	b.record(c, errors.New("still broken"), later)
+	if b.allow(later.Add(time.Second)) {
+		t.Errorf("expected failed probe to open breaker")
+	}
+}
+
+func TestBreakerWindow(t *testing.T) {
+	c := &breakerConfig{failures: 2, window: time.Minute, cooldown: time.Minute}
+	b := &breaker{}
+	now := time.Now()
+
+	b.record(c, errors.New("broken"), now)
+	b.record(c, errors.New("broken"), now.Add(2*time.Minute))
+	if !b.allow(now.Add(2 * time.Minute)) {
+		t.Errorf("expected failures outside the window to be forgotten")
+	}
+}
@@ -0,0 +1,62 @@
+package scientist
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+var ErrInjectedFault = errors.New("[scientist] injected fault")
+
+// Faults are injected into candidates with InjectFaults.
+type Faults struct {
+	// Latency is added before each candidate runs, plus a random amount up to
+	// Jitter. Candidates stop waiting when their context is done.
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// ErrorRate is the percentage of candidate runs that fail with Err
+	// instead of running. Err defaults to ErrInjectedFault.
+	ErrorRate float64
+	Err       error
+}
+
+// InjectFaults slows down or fails candidates on purpose, to check that the
+// experiment's timeouts, shedding, and overhead behave before pointing it at
+// a slow system. The control is never affected.
+func (e *Experiment) InjectFaults(f Faults) {
+	if !e.configurable("InjectFaults") {
+		return
+	}
+
+	if f.Err == nil {
+		f.Err = ErrInjectedFault
+	}
+	e.faults = &f
+}
+
+func faulty(f *Faults, b behaviorFunc) behaviorFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		delay := f.Latency
+		if f.Jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(f.Jitter)))
+		}
+
+		if delay > 0 {
+			t := time.NewTimer(delay)
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				t.Stop()
+				return nil, context.Cause(ctx)
+			}
+		}
+
+		if f.ErrorRate > 0 && rand.Float64()*100 < f.ErrorRate {
+			return nil, f.Err
+		}
+
+		return b(ctx)
+	}
+}
@@ -0,0 +1,68 @@
+package scientist
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInjectFaultsErrors(t *testing.T) {
+	e := New("inject-faults-errors")
+	e.InjectFaults(Faults{ErrorRate: 100})
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		t.Errorf("Expected the candidate not to run")
+		return 1, nil
+	})
+
+	var published Result
+	e.Publish(func(r Result) error {
+		published = r
+		return nil
+	})
+
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+
+	if len(published.Candidates) != 1 || !errors.Is(published.Candidates[0].Err, ErrInjectedFault) {
+		t.Errorf("Expected an injected fault: %+v", published.Candidates)
+	}
+}
+
+func TestInjectFaultsLatency(t *testing.T) {
+	e := New("inject-faults-latency")
+	e.EnableConcurrency(10 * time.Millisecond)
+	e.InjectFaults(Faults{Latency: time.Second})
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	var published Result
+	e.Publish(func(r Result) error {
+		published = r
+		return nil
+	})
+
+	started := time.Now()
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+
+	if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the slow candidate to be abandoned, took %v", elapsed)
+	}
+
+	if published.Control.Runtime > 500*time.Millisecond {
+		t.Errorf("Expected the control not to be slowed down: %v", published.Control.Runtime)
+	}
+
+	if len(published.TimedOut) != 1 {
+		t.Errorf("Expected the candidate to time out: %+v", published.Candidates)
+	}
+}
@@ -0,0 +1,119 @@
+package scientist
+
+import "reflect"
+
+// CloneValues copies each observed value with fn as soon as the behavior
+// returns. The result keeps the copies, and Run returns the control's
+// original value, so the caller can change it while async comparisons or a
+// PublishQueue still read the result. DeepCopy works for most values.
+func (e *Experiment) CloneValues(fn func(v interface{}) interface{}) {
+	if !e.callback("CloneValues", fn) {
+		return
+	}
+
+	e.cloner = fn
+}
+
+func cloneObservation(e *Experiment, o *Observation, control bool) {
+	// hashes can't be changed, so there's nothing to copy.
+	if e.cloner == nil || e.hashValues || o.Err != nil || o.SkipReason != "" {
+		return
+	}
+
+	if control {
+		o.returned = o.Value
+		o.substituted = true
+	}
+	o.Value = e.cloner(o.Value)
+}
+
+type copyKey struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// DeepCopy copies pointers, maps, slices, arrays, interfaces, and the
+// exported fields of structs. Unexported fields, channels, and funcs are
+// shared with the original. Pointers to the same value are copied once, so
+// cycles are kept.
+func DeepCopy(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(v), make(map[copyKey]reflect.Value)).Interface()
+}
+
+func deepCopy(v reflect.Value, seen map[copyKey]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+
+		key := copyKey{v.Type(), v.Pointer()}
+		if c, ok := seen[key]; ok {
+			return c
+		}
+
+		c := reflect.New(v.Type().Elem())
+		seen[key] = c
+		c.Elem().Set(deepCopy(v.Elem(), seen))
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(iter.Key(), deepCopy(iter.Value(), seen))
+		}
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		copyElems(c, v, seen)
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		copyElems(c, v, seen)
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := c.Field(i); f.CanSet() {
+				f.Set(deepCopy(v.Field(i), seen))
+			}
+		}
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), seen))
+		return c
+	default:
+		return v
+	}
+}
+
+func copyElems(dst, src reflect.Value, seen map[copyKey]reflect.Value) {
+	switch src.Type().Elem().Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		reflect.Copy(dst, src)
+		return
+	}
+
+	for i := 0; i < src.Len(); i++ {
+		dst.Index(i).Set(deepCopy(src.Index(i), seen))
+	}
+}
@@ -0,0 +1,93 @@
+package scientist
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type cloneWidget struct {
+	Name   string
+	Tags   []string
+	Attrs  map[string]interface{}
+	Parent *cloneWidget
+	Sizes  [2][]int
+	hidden *int
+}
+
+func TestDeepCopy(t *testing.T) {
+	hidden := 1
+	w := &cloneWidget{
+		Name:   "widget",
+		Tags:   []string{"a", "b"},
+		Attrs:  map[string]interface{}{"list": []int{1, 2}},
+		Sizes:  [2][]int{{1}, {2}},
+		hidden: &hidden,
+	}
+	w.Parent = w
+
+	c := DeepCopy(w).(*cloneWidget)
+	if !reflect.DeepEqual(w, c) {
+		t.Fatalf("Expected an equal copy: %+v", c)
+	}
+
+	w.Tags[0] = "changed"
+	w.Attrs["list"].([]int)[0] = 9
+	w.Sizes[0][0] = 9
+	if c.Tags[0] != "a" || c.Attrs["list"].([]int)[0] != 1 || c.Sizes[0][0] != 1 {
+		t.Errorf("Expected the copy not to change: %+v", c)
+	}
+
+	if c.Parent != c {
+		t.Errorf("Expected the cycle to be kept")
+	}
+
+	if c.hidden != w.hidden {
+		t.Errorf("Expected unexported fields to be shared")
+	}
+
+	if DeepCopy(nil) != nil || DeepCopy(3) != 3 {
+		t.Errorf("Unexpected copies of simple values")
+	}
+}
+
+func TestCloneValues(t *testing.T) {
+	release := make(chan struct{})
+	published := make(chan Result, 1)
+
+	e := New("clone-values")
+	e.CloneValues(DeepCopy)
+	e.EnableAsyncComparison()
+	e.Use(func() (interface{}, error) {
+		return map[string]int{"count": 1}, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return map[string]int{"count": 1}, nil
+	})
+	e.Compare(func(control, candidate interface{}) (bool, error) {
+		<-release
+		return reflect.DeepEqual(control, candidate), nil
+	})
+	e.Publish(func(r Result) error {
+		published <- r
+		return nil
+	})
+
+	v, err := e.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the caller owns the control's value now
+	v.(map[string]int)["count"] = 2
+	close(release)
+
+	select {
+	case r := <-published:
+		if !r.IsMatched() {
+			t.Errorf("Expected the copies to match: %v, %v", r.Control.Value, r.Candidates[0].Value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the result to be published")
+	}
+}
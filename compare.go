@@ -0,0 +1,121 @@
+package scientist
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// DeepComparator compares values with cmp.Equal. Unlike the default
+// reflect.DeepEqual comparator, it can be customized with options to ignore
+// fields, compare unexported fields, or define custom equality.
+func DeepComparator(opts ...cmp.Option) func(control, candidate interface{}) (bool, error) {
+	return func(control, candidate interface{}) (equal bool, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				equal = false
+				err = fmt.Errorf("[scientist] cmp.Equal panicked: %v", p)
+			}
+		}()
+
+		return cmp.Equal(control, candidate, opts...), nil
+	}
+}
+
+// CompareOptions compares values with DeepComparator, and uses the same
+// options for mismatch diffs.
+func (e *Experiment) CompareOptions(opts ...cmp.Option) {
+	if !e.configurable("CompareOptions") {
+		return
+	}
+
+	e.comparator = DeepComparator(opts...)
+	e.cmpOptions = opts
+}
+
+// CompareJSON compares values as JSON, ignoring key order and whitespace.
+// Strings, byte slices, and json.RawMessage values are parsed as JSON. Other
+// values are encoded with json.Marshal first. Integers are compared exactly,
+// even past the 2^53 a float64 can hold, so large IDs don't compare equal.
+func CompareJSON(control, candidate interface{}) (bool, error) {
+	a, err := normalizeJSON(control)
+	if err != nil {
+		return false, fmt.Errorf("[scientist] control is not valid JSON: %w", err)
+	}
+
+	b, err := normalizeJSON(candidate)
+	if err != nil {
+		return false, fmt.Errorf("[scientist] candidate is not valid JSON: %w", err)
+	}
+
+	return reflect.DeepEqual(a, b), nil
+}
+
+func normalizeJSON(v interface{}) (interface{}, error) {
+	var data []byte
+	switch t := v.(type) {
+	case string:
+		data = []byte(t)
+	case []byte:
+		data = t
+	case json.RawMessage:
+		data = t
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return nil, err
+		}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var normalized interface{}
+	if err := dec.Decode(&normalized); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return normalizeNumbers(normalized), nil
+}
+
+// normalizeNumbers replaces each json.Number with an int64 if it's an integer,
+// like 1 or 2.0, and a float64 otherwise, so numbers written differently still
+// compare equal.
+func normalizeNumbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		if !strings.ContainsAny(t.String(), ".eE") {
+			// too big for an int64, but still compared exactly.
+			return t.String()
+		}
+		f, err := t.Float64()
+		if err != nil {
+			return t.String()
+		}
+		if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			return int64(f)
+		}
+		return f
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = normalizeNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = normalizeNumbers(e)
+		}
+	}
+	return v
+}
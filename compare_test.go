@@ -0,0 +1,43 @@
+package scientist
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCompareJSON(t *testing.T) {
+	type user struct {
+		Login string `json:"login"`
+		Admin bool   `json:"admin"`
+	}
+
+	tests := []struct {
+		control, candidate interface{}
+		expected           bool
+	}{
+		{`{"login":"alice","admin":false}`, `{ "admin": false, "login": "alice" }`, true},
+		{`{"login":"alice"}`, []byte(`{"login":"bob"}`), false},
+		{user{"alice", true}, `{"admin":true,"login":"alice"}`, true},
+		{json.RawMessage(`[1, 2.0]`), []int{1, 2}, true},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`{"id":9007199254740993}`, `{"id":9007199254740992}`, false},
+		{`{"id":9007199254740993}`, map[string]int64{"id": 9007199254740993}, true},
+		{`[1e2, 0.5]`, `[100, 5e-1]`, true},
+		{`18446744073709551615`, `18446744073709551614`, false},
+	}
+
+	for _, test := range tests {
+		equal, err := CompareJSON(test.control, test.candidate)
+		if err != nil {
+			t.Errorf("CompareJSON(%v, %v): %v", test.control, test.candidate, err)
+		}
+
+		if equal != test.expected {
+			t.Errorf("CompareJSON(%v, %v): expected %t", test.control, test.candidate, test.expected)
+		}
+	}
+
+	if _, err := CompareJSON(`{`, `{}`); err == nil {
+		t.Errorf("Expected an error for invalid JSON")
+	}
+}
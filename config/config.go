@@ -0,0 +1,126 @@
+// Package config loads experiment settings from a YAML or JSON file:
+//
+//	experiments:
+//	  widget-permissions:
+//	    enabled: true
+//	    percent: 10
+//	    concurrent: true
+//	    timeout: 250ms
+//	    publish_percent: 1
+//
+// Settings apply to every experiment with the same name, including ones
+// created after the file is loaded. Settings left out of the file fall back
+// to the experiment's own.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"scientist"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Experiments map[string]Experiment `json:"experiments"`
+}
+
+type Experiment struct {
+	Enabled        *bool     `json:"enabled"`
+	Percent        *float64  `json:"percent"`
+	Concurrent     *bool     `json:"concurrent"`
+	Timeout        *Duration `json:"timeout"`
+	PublishPercent *float64  `json:"publish_percent"`
+}
+
+// Duration is a time.Duration written as a string, like "250ms".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"250ms\": %s", data)
+	}
+
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Load reads a config file. Files ending in .yaml or .yml are parsed as YAML,
+// and anything else as JSON.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseYAML(data)
+	default:
+		return ParseJSON(data)
+	}
+}
+
+func ParseJSON(data []byte) (*Config, error) {
+	c := &Config{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ParseYAML converts the YAML to JSON first, so both formats share the JSON
+// field names and parsing.
+func ParseYAML(data []byte) (*Config, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+
+	if v == nil {
+		return &Config{}, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return ParseJSON(data)
+}
+
+// Apply configures every experiment in the config.
+func (c *Config) Apply() {
+	for name, e := range c.Experiments {
+		scientist.Configure(name, e.Settings())
+	}
+}
+
+func (e Experiment) Settings() scientist.Settings {
+	s := scientist.Settings{
+		Enabled:        e.Enabled,
+		Percent:        e.Percent,
+		Concurrent:     e.Concurrent,
+		PublishPercent: e.PublishPercent,
+	}
+
+	if e.Timeout != nil {
+		timeout := time.Duration(*e.Timeout)
+		s.Timeout = &timeout
+	}
+
+	return s
+}
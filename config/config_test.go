@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"scientist"
+	"testing"
+	"time"
+)
+
+func TestLoadYAML(t *testing.T) {
+	c := load(t, "config.yml", `
+experiments:
+  widget-permissions:
+    enabled: false
+    percent: 10
+    concurrent: true
+    timeout: 250ms
+    publish_percent: 1
+`)
+
+	e, ok := c.Experiments["widget-permissions"]
+	if !ok {
+		t.Fatalf("Unexpected experiments: %v", c.Experiments)
+	}
+
+	s := e.Settings()
+	if s.Enabled == nil || *s.Enabled {
+		t.Errorf("Unexpected enabled: %v", s.Enabled)
+	}
+
+	if s.Percent == nil || *s.Percent != 10 {
+		t.Errorf("Unexpected percent: %v", s.Percent)
+	}
+
+	if s.Concurrent == nil || !*s.Concurrent {
+		t.Errorf("Unexpected concurrent: %v", s.Concurrent)
+	}
+
+	if s.Timeout == nil || *s.Timeout != 250*time.Millisecond {
+		t.Errorf("Unexpected timeout: %v", s.Timeout)
+	}
+
+	if s.PublishPercent == nil || *s.PublishPercent != 1 {
+		t.Errorf("Unexpected publish percent: %v", s.PublishPercent)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	c := load(t, "config.json", `{"experiments": {"widget-permissions": {"percent": 10}}}`)
+
+	s := c.Experiments["widget-permissions"].Settings()
+	if s.Percent == nil || *s.Percent != 10 {
+		t.Errorf("Unexpected percent: %v", s.Percent)
+	}
+
+	if s.Enabled != nil || s.Timeout != nil {
+		t.Errorf("Unexpected settings: %+v", s)
+	}
+}
+
+func TestLoadInvalidTimeout(t *testing.T) {
+	path := write(t, "config.json", `{"experiments": {"widget-permissions": {"timeout": 250}}}`)
+	if _, err := Load(path); err == nil {
+		t.Errorf("expected an error for a numeric timeout")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	path := write(t, "config.yml", `
+experiments:
+  config-watch:
+    enabled: false
+`)
+
+	errs := make(chan error, 10)
+	w, err := Watch(path, func(err error) {
+		errs <- err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	defer scientist.Configure("config-watch", scientist.Settings{})
+
+	e := scientist.New("config-watch")
+	if !e.Disabled() {
+		t.Errorf("expected experiment to be disabled")
+	}
+
+	if err := os.WriteFile(path, []byte("experiments: {config-watch: {enabled: [}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected an error for the invalid file")
+	}
+
+	if !e.Disabled() {
+		t.Errorf("expected invalid file to keep previous settings")
+	}
+
+	if err := os.WriteFile(path, []byte("experiments: {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for e.Disabled() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected experiment to be enabled after reload")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func load(t *testing.T, name, data string) *Config {
+	c, err := Load(write(t, name, data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func write(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"scientist"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher reloads a config file whenever it changes.
+type Watcher struct {
+	path    string
+	onError func(error)
+	watcher *fsnotify.Watcher
+	names   map[string]bool
+	wg      sync.WaitGroup
+}
+
+// Watch loads and applies a config file, and applies it again every time it
+// changes. If a reload fails, the previous settings are kept and the error is
+// passed to onError, which may be nil. Experiments removed from the file go
+// back to their own settings.
+func Watch(path string, onError func(error)) (*Watcher, error) {
+	w := &Watcher{
+		path:    filepath.Clean(path),
+		onError: onError,
+		names:   make(map[string]bool),
+	}
+
+	c, err := Load(w.path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Watch the directory, since editors and config management tools often
+	// replace the file instead of writing to it.
+	w.watcher, err = fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.watcher.Add(filepath.Dir(w.path)); err != nil {
+		w.watcher.Close()
+		return nil, err
+	}
+
+	w.apply(c)
+
+	w.wg.Add(1)
+	go w.watch()
+	return w, nil
+}
+
+func (w *Watcher) Close() error {
+	err := w.watcher.Close()
+	w.wg.Wait()
+	return err
+}
+
+func (w *Watcher) watch() {
+	defer w.wg.Done()
+
+	for {
+		select {
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+
+			if filepath.Clean(event.Name) != w.path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			// Writers truncate the file first. An empty file is most likely
+			// still being written, so wait for the next write.
+			if info, err := os.Stat(w.path); err == nil && info.Size() == 0 {
+				continue
+			}
+
+			c, err := Load(w.path)
+			if err != nil {
+				w.error(err)
+				continue
+			}
+
+			w.apply(c)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			w.error(err)
+		}
+	}
+}
+
+func (w *Watcher) apply(c *Config) {
+	for name := range w.names {
+		if _, ok := c.Experiments[name]; !ok {
+			scientist.Configure(name, scientist.Settings{})
+			delete(w.names, name)
+		}
+	}
+
+	for name := range c.Experiments {
+		w.names[name] = true
+	}
+
+	c.Apply()
+}
+
+func (w *Watcher) error(err error) {
+	if w.onError != nil {
+		w.onError(err)
+	}
+}
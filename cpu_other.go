@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package scientist
+
+import "time"
+
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,30 @@
+package scientist
+
+import "context"
+
+type DeadlinePolicy int
+
+const (
+	// DeadlineRun bounds the whole run with the caller's context. Candidates
+	// that haven't finished when it's done are abandoned.
+	DeadlineRun DeadlinePolicy = iota
+
+	// DeadlineCandidates bounds only the candidates. The control runs without
+	// the caller's deadline or cancellation, but keeps its values.
+	DeadlineCandidates
+)
+
+func (e *Experiment) HonorDeadline(policy DeadlinePolicy) {
+	if !e.configurable("HonorDeadline") {
+		return
+	}
+
+	e.deadlinePolicy = policy
+}
+
+func controlContext(ctx context.Context, e *Experiment) context.Context {
+	if e.deadlinePolicy == DeadlineCandidates {
+		return context.WithoutCancel(ctx)
+	}
+	return ctx
+}
@@ -0,0 +1,73 @@
+package scientist
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestHonorDeadlineRun(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	e := New("deadline-run")
+	e.UseContext(func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		t.Errorf("Expected the candidate not to start after the deadline")
+		return 1, nil
+	})
+
+	var aborted []string
+	e.Abort(func(name string) {
+		aborted = append(aborted, name)
+	})
+
+	var result Result
+	e.Publish(func(r Result) error {
+		result = r
+		return nil
+	})
+
+	if v, err := e.RunContext(ctx); v != 1 || err != nil {
+		t.Errorf("Unexpected control: %v, %v", v, err)
+	}
+
+	assertObservationNames(t, "timed out", result.TimedOut, []string{"candidate"})
+	if len(aborted) != 1 || aborted[0] != "candidate" {
+		t.Errorf("Unexpected aborted candidates: %v", aborted)
+	}
+}
+
+func TestHonorDeadlineCandidates(t *testing.T) {
+	for _, concurrent := range []bool{false, true} {
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), argsKey{}, "value"))
+
+		e := New("deadline-candidates")
+		if concurrent {
+			e.EnableControlFirst(0)
+		}
+		e.HonorDeadline(DeadlineCandidates)
+		e.UseContext(func(ctx context.Context) (interface{}, error) {
+			cancel()
+			if ctx.Err() != nil || ctx.Value(argsKey{}) != "value" {
+				t.Errorf("Expected the control to keep its context values without cancellation")
+			}
+			return 1, nil
+		})
+		e.TryContext(func(ctx context.Context) (interface{}, error) {
+			return nil, ctx.Err()
+		})
+
+		r := run(e, "control", runOptions{ctx: ctx})
+		if r.Control.Value != 1 {
+			t.Errorf("Unexpected control value: %v", r.Control.Value)
+		}
+
+		if err := r.Candidates[0].Err; err != context.Canceled || !r.Candidates[0].abandoned {
+			t.Errorf("Expected the candidate to be abandoned, got: %v", err)
+		}
+	}
+}
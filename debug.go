@@ -0,0 +1,95 @@
+package scientist
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type debugStats struct {
+	Name         string     `json:"name"`
+	Enabled      bool       `json:"enabled"`
+	Runs         int64      `json:"runs"`
+	Matched      int64      `json:"matched"`
+	Mismatched   int64      `json:"mismatched"`
+	Ignored      int64      `json:"ignored"`
+	Skipped      int64      `json:"skipped"`
+	MatchRate    float64    `json:"match_rate"`
+	MismatchRate float64    `json:"mismatch_rate"`
+	LastMismatch *time.Time `json:"last_mismatch,omitempty"`
+}
+
+// DebugHandler serves every experiment's stats as JSON. With allowWrites, it
+// also flips an experiment's kill switch on POST. Otherwise it only serves
+// GET and HEAD, so it's safe to expose wherever the stats are.
+func DebugHandler(allowWrites bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		serveDebug(w, req, allowWrites)
+	})
+}
+
+func serveDebug(w http.ResponseWriter, req *http.Request, allowWrites bool) {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		writeDebugStats(w, AllStats())
+	case http.MethodPost:
+		if !allowWrites {
+			methodNotAllowed(w, "GET, HEAD")
+			return
+		}
+
+		e, ok := Lookup(req.FormValue("experiment"))
+		if !ok {
+			http.Error(w, "experiment not found", http.StatusNotFound)
+			return
+		}
+
+		switch req.FormValue("action") {
+		case "enable":
+			e.Enable()
+		case "disable":
+			e.Disable()
+		default:
+			http.Error(w, `action must be "enable" or "disable"`, http.StatusBadRequest)
+			return
+		}
+
+		writeDebugStats(w, []Stats{e.Stats()})
+	default:
+		if allowWrites {
+			methodNotAllowed(w, "GET, HEAD, POST")
+		} else {
+			methodNotAllowed(w, "GET, HEAD")
+		}
+	}
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+}
+
+func writeDebugStats(w http.ResponseWriter, stats []Stats) {
+	payload := make([]debugStats, len(stats))
+	for i, s := range stats {
+		payload[i] = debugStats{
+			Name:         s.Name,
+			Enabled:      s.Enabled,
+			Runs:         s.Runs,
+			Matched:      s.Matched,
+			Mismatched:   s.Mismatched,
+			Ignored:      s.Ignored,
+			Skipped:      s.Skipped,
+			MatchRate:    s.MatchRate(),
+			MismatchRate: s.MismatchRate(),
+		}
+
+		if !s.LastMismatch.IsZero() {
+			last := s.LastMismatch
+			payload[i].LastMismatch = &last
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payload)
+}
@@ -0,0 +1,133 @@
+package scientist
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	name := uniqueName("debug-handler")
+	e := New(name)
+	t.Cleanup(e.Enable)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Run()
+
+	server := httptest.NewServer(DebugHandler(true))
+	defer server.Close()
+
+	stats := getDebugStats(t, server.URL)
+	if s, ok := stats[name]; !ok {
+		t.Errorf("experiment not listed: %v", stats)
+	} else if !s.Enabled || s.Runs != 1 || s.Mismatched != 1 || s.MismatchRate != 1 || s.LastMismatch == nil {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+
+	res, err := http.PostForm(server.URL, url.Values{"experiment": {name}, "action": {"disable"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("Unexpected status: %d", res.StatusCode)
+	}
+
+	if !e.Disabled() {
+		t.Errorf("expected experiment to be disabled")
+	}
+
+	if s := getDebugStats(t, server.URL)[name]; s.Enabled {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+
+	res, err = http.PostForm(server.URL, url.Values{"experiment": {name}, "action": {"enable"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if e.Disabled() {
+		t.Errorf("expected experiment to be enabled")
+	}
+}
+
+func TestDebugHandlerErrors(t *testing.T) {
+	New("debug-handler-errors")
+
+	server := httptest.NewServer(DebugHandler(true))
+	defer server.Close()
+
+	tests := []struct {
+		values url.Values
+		status int
+	}{
+		{url.Values{"experiment": {"missing"}, "action": {"disable"}}, http.StatusNotFound},
+		{url.Values{"experiment": {"debug-handler-errors"}, "action": {"explode"}}, http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+		res, err := http.PostForm(server.URL, test.values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if res.StatusCode != test.status {
+			t.Errorf("Unexpected status for %v: %d", test.values, res.StatusCode)
+		}
+	}
+}
+
+func TestDebugHandlerReadOnly(t *testing.T) {
+	name := uniqueName("debug-handler-read-only")
+	e := New(name)
+	t.Cleanup(e.Enable)
+
+	server := httptest.NewServer(DebugHandler(false))
+	defer server.Close()
+
+	res, err := http.PostForm(server.URL, url.Values{"experiment": {name}, "action": {"disable"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusMethodNotAllowed || res.Header.Get("Allow") != "GET, HEAD" {
+		t.Errorf("Unexpected response: %d %v", res.StatusCode, res.Header)
+	}
+
+	if e.Disabled() {
+		t.Errorf("expected experiment to stay enabled")
+	}
+
+	if _, ok := getDebugStats(t, server.URL)[name]; !ok {
+		t.Errorf("experiment not listed")
+	}
+}
+
+func getDebugStats(t *testing.T, url string) map[string]debugStats {
+	res, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var stats []debugStats
+	if err := json.NewDecoder(res.Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]debugStats, len(stats))
+	for _, s := range stats {
+		byName[s.Name] = s
+	}
+	return byName
+}
@@ -0,0 +1,86 @@
+package scientist
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// dedupWindow remembers when each mismatch was last published, shared by
+// every experiment with the same name.
+type dedupWindow struct {
+	mu     sync.Mutex
+	seen   map[uint64]time.Time
+	pruned time.Time
+}
+
+// DedupMismatches publishes a mismatch only once per window. Mismatches are
+// the same when they have the same experiment, candidate, and diff of the
+// cleaned values. A result is skipped, including its OnMismatch callbacks,
+// when every mismatched candidate was published within the window. Run and
+// AfterRun still see every mismatch.
+func (e *Experiment) DedupMismatches(window time.Duration) {
+	if !e.configurable("DedupMismatches") {
+		return
+	}
+
+	e.dedupWindow = window
+}
+
+// duplicate checks the result's mismatches after they're cleaned, and
+// remembers the new ones.
+func (e *Experiment) duplicate(r Result) bool {
+	if e.dedupWindow <= 0 || !r.IsMismatched() {
+		return false
+	}
+
+	hashes := make([]uint64, len(r.Mismatched))
+	for i, c := range r.Mismatched {
+		hashes[i] = mismatchHash(e, r.Control, c)
+	}
+
+	return e.state.dedup.seenAll(hashes, e.dedupWindow, time.Now())
+}
+
+func (d *dedupWindow) seenAll(hashes []uint64, window time.Duration, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.seen == nil {
+		d.seen = make(map[uint64]time.Time)
+	}
+
+	if now.Sub(d.pruned) > window {
+		for h, t := range d.seen {
+			if now.Sub(t) > window {
+				delete(d.seen, h)
+			}
+		}
+		d.pruned = now
+	}
+
+	all := true
+	for _, h := range hashes {
+		if t, ok := d.seen[h]; ok && now.Sub(t) <= window {
+			continue
+		}
+		d.seen[h] = now
+		all = false
+	}
+	return all
+}
+
+func mismatchHash(e *Experiment, control, candidate *Observation) uint64 {
+	d := candidate.Diff
+	if control.Err == nil && candidate.Err == nil && control.CleanErr == nil && candidate.CleanErr == nil {
+		d = diffValues(e, control.Cleaned, candidate.Cleaned)
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(e.Name))
+	h.Write([]byte{0})
+	h.Write([]byte(candidate.Name))
+	h.Write([]byte{0})
+	h.Write([]byte(d))
+	return h.Sum64()
+}
@@ -0,0 +1,98 @@
+package scientist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDedupMismatches(t *testing.T) {
+	name := uniqueName("dedup-mismatches")
+	published := 0
+	mismatches := 0
+	run := func(candidate int) {
+		e := New(name)
+		e.DedupMismatches(time.Hour)
+		e.Use(func() (interface{}, error) {
+			return 1, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return candidate, nil
+		})
+		e.Publish(func(r Result) error {
+			published++
+			return nil
+		})
+		e.OnMismatch(func(r Result) error {
+			mismatches++
+			return nil
+		})
+		e.Run()
+	}
+
+	run(2)
+	run(2)
+	run(3)
+	run(1)
+	run(1)
+
+	if published != 4 {
+		t.Errorf("Expected 4 published results, got %d", published)
+	}
+
+	if mismatches != 2 {
+		t.Errorf("Expected 2 mismatches, got %d", mismatches)
+	}
+}
+
+func TestDedupMismatchesCleaned(t *testing.T) {
+	name := uniqueName("dedup-mismatches-cleaned")
+	published := 0
+	for _, id := range []int{1, 2} {
+		id := id
+		e := New(name)
+		e.DedupMismatches(time.Hour)
+		e.Use(func() (interface{}, error) {
+			return []int{id, 1}, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return []int{id, 2}, nil
+		})
+		e.Clean(func(v interface{}) (interface{}, error) {
+			return v.([]int)[1], nil
+		})
+		e.Publish(func(r Result) error {
+			published++
+			return nil
+		})
+		e.Run()
+	}
+
+	if published != 1 {
+		t.Errorf("Expected 1 published result, got %d", published)
+	}
+}
+
+func TestDedupWindowExpires(t *testing.T) {
+	var d dedupWindow
+	now := time.Now()
+
+	if d.seenAll([]uint64{1}, time.Minute, now) {
+		t.Errorf("Expected a new mismatch")
+	}
+
+	if !d.seenAll([]uint64{1}, time.Minute, now.Add(30*time.Second)) {
+		t.Errorf("Expected a duplicate mismatch")
+	}
+
+	if d.seenAll([]uint64{1, 2}, time.Minute, now.Add(45*time.Second)) {
+		t.Errorf("Expected a new mismatch")
+	}
+
+	if d.seenAll([]uint64{1}, time.Minute, now.Add(2*time.Minute)) {
+		t.Errorf("Expected an expired mismatch")
+	}
+
+	if len(d.seen) != 1 {
+		t.Errorf("Expected expired mismatches to be pruned: %v", d.seen)
+	}
+}
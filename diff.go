@@ -0,0 +1,50 @@
+package scientist
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// exportAll lets cmp report differences in unexported fields instead of
+// panicking.
+var exportAll = cmp.Exporter(func(reflect.Type) bool { return true })
+
+// diff describes how a candidate differs from the control, in cmp.Diff
+// format: "-" lines are from the control, "+" lines from the candidate.
+func diff(e *Experiment, control, candidate *Observation) string {
+	if control.Err != nil || candidate.Err != nil {
+		return cmp.Diff(errString(control.Err), errString(candidate.Err))
+	}
+
+	return diffValues(e, control.Value, candidate.Value)
+}
+
+func diffValues(e *Experiment, control, candidate interface{}) (d string) {
+	defer func() {
+		if p := recover(); p != nil {
+			d = fmt.Sprintf("-: %#v\n+: %#v\n", control, candidate)
+		}
+	}()
+
+	opts := append([]cmp.Option{exportAll}, e.cmpOptions...)
+	return cmp.Diff(control, candidate, opts...)
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
+
+// Diff returns the diffs of all mismatched candidates.
+func (r Result) Diff() string {
+	var b strings.Builder
+	for _, o := range r.Mismatched {
+		fmt.Fprintf(&b, "%s:\n%s", o.Name, o.Diff)
+	}
+	return b.String()
+}
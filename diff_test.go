@@ -0,0 +1,120 @@
+package scientist
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+type diffUser struct {
+	Login string
+	email string
+}
+
+func TestMismatchDiff(t *testing.T) {
+	e := New("diff")
+	e.ErrorOnMismatches = true
+	e.Use(func() (interface{}, error) {
+		return diffUser{"alice", "alice@example.com"}, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return diffUser{"alice", "bob@example.com"}, nil
+	})
+	e.Behavior("errored", func() (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+
+	published := false
+	e.Publish(func(r Result) error {
+		published = true
+
+		for _, o := range r.Mismatched {
+			if o.Diff == "" {
+				t.Errorf("No diff for %q", o.Name)
+			}
+		}
+
+		return nil
+	})
+
+	_, err := e.Run()
+	merr, ok := err.(MismatchError)
+	if !ok {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	d := merr.Diff()
+	for _, expected := range []string{"candidate:", "alice@example.com", "bob@example.com", "errored:", "boom"} {
+		if !strings.Contains(d, expected) {
+			t.Errorf("Expected %q in diff:\n%s", expected, d)
+		}
+	}
+
+	if !published {
+		t.Errorf("results never published")
+	}
+}
+
+func TestCompareOptions(t *testing.T) {
+	e := New("options")
+	e.Use(func() (interface{}, error) {
+		return diffUser{"alice", "alice@example.com"}, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return diffUser{"alice", "bob@example.com"}, nil
+	})
+	e.Behavior("bob", func() (interface{}, error) {
+		return diffUser{"bob", "alice@example.com"}, nil
+	})
+	e.CompareOptions(cmpopts.IgnoreUnexported(diffUser{}))
+
+	r := Run(e, "control")
+	if len(r.Errors) != 0 {
+		t.Errorf("Unexpected experiment errors: %v", r.Errors)
+	}
+
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"bob"})
+	if d := r.Mismatched[0].Diff; strings.Contains(d, "email") || !strings.Contains(d, "Login") {
+		t.Errorf("Unexpected diff:\n%s", d)
+	}
+}
+
+func TestDeepComparatorPanic(t *testing.T) {
+	equal, err := DeepComparator()(diffUser{}, diffUser{})
+	if equal || err == nil {
+		t.Errorf("Expected an error comparing unexported fields, got %t, %v", equal, err)
+	}
+}
+
+func TestMismatchErrorUnwrap(t *testing.T) {
+	boom := errors.New("boom")
+	e := New("mismatch-unwrap")
+	e.ErrorOnMismatches = true
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return nil, boom
+	})
+	e.Behavior("correct", func() (interface{}, error) {
+		return 1, nil
+	})
+
+	_, err := e.Run()
+	if !errors.Is(err, ErrMismatch) || !errors.Is(err, boom) {
+		t.Fatalf("Expected ErrMismatch and the candidate error, got: %v", err)
+	}
+
+	var merr MismatchError
+	if !errors.As(err, &merr) {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if merr.Control.Value != 1 {
+		t.Errorf("Unexpected control: %v", merr.Control.Value)
+	}
+
+	assertObservationNames(t, "mismatched", merr.Mismatched, []string{"candidate"})
+}
@@ -0,0 +1,27 @@
+package scientist
+
+import "sync/atomic"
+
+const SkipDisabled = "disabled"
+
+var allDisabled int32
+
+func DisableAll() {
+	atomic.StoreInt32(&allDisabled, 1)
+}
+
+func EnableAll() {
+	atomic.StoreInt32(&allDisabled, 0)
+}
+
+func (e *Experiment) Disable() {
+	atomic.StoreInt32(&e.state.disabled, 1)
+}
+
+func (e *Experiment) Enable() {
+	atomic.StoreInt32(&e.state.disabled, 0)
+}
+
+func (e *Experiment) Disabled() bool {
+	return atomic.LoadInt32(&allDisabled) == 1 || atomic.LoadInt32(&e.state.disabled) == 1 || e.configDisabled() || envDisabled(e.Name) || e.expiredNow()
+}
@@ -0,0 +1,89 @@
+package scientist
+
+import "testing"
+
+func TestDisable(t *testing.T) {
+	e := New("disable")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		t.Errorf("did not expect candidate to run")
+		return 2, nil
+	})
+	e.RunIf(func() (bool, error) {
+		t.Errorf("did not expect RunIf callback to run")
+		return true, nil
+	})
+
+	var published []Result
+	e.Publish(func(r Result) error {
+		published = append(published, r)
+		return nil
+	})
+
+	e.Disable()
+	v, err := e.Run()
+	if v != 1 || err != nil {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+
+	if !e.Disabled() {
+		t.Errorf("expected experiment to be disabled")
+	}
+
+	if len(published) != 1 {
+		t.Fatalf("Unexpected published results: %d", len(published))
+	}
+
+	r := published[0]
+	if r.SkipReason != SkipDisabled {
+		t.Errorf("Unexpected skip reason: %q", r.SkipReason)
+	}
+
+	if r.IsMatched() {
+		t.Errorf("expected disabled result not to match")
+	}
+
+	if r.Control.Value != 1 {
+		t.Errorf("Unexpected control value: %v", r.Control.Value)
+	}
+
+	assertObservationNames(t, "candidate", r.Candidates, []string{})
+
+	e.Enable()
+	if e.Disabled() {
+		t.Errorf("expected experiment to be enabled")
+	}
+}
+
+func TestDisableAll(t *testing.T) {
+	DisableAll()
+	defer EnableAll()
+
+	e := basicExperiment()
+	if !e.Disabled() {
+		t.Errorf("expected experiment to be disabled")
+	}
+
+	var published Result
+	e.Publish(func(r Result) error {
+		published = r
+		return nil
+	})
+
+	e.Run()
+	if published.SkipReason != SkipDisabled {
+		t.Errorf("Unexpected skip reason: %q", published.SkipReason)
+	}
+
+	EnableAll()
+	if e.Disabled() {
+		t.Errorf("expected experiment to be enabled")
+	}
+
+	e.Run()
+	if published.SkipReason != "" {
+		t.Errorf("Unexpected skip reason: %q", published.SkipReason)
+	}
+}
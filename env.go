@@ -0,0 +1,95 @@
+package scientist
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	envDisable       = "SCIENTIST_DISABLE"
+	envPercentPrefix = "SCIENTIST_PERCENT_"
+)
+
+// envConfig holds an *envSettings, so runs can read it without a lock.
+var envConfig atomic.Value
+
+type envSettings struct {
+	disabled map[string]bool
+	percents map[string]float64
+}
+
+func init() {
+	if err := LoadEnv(); err != nil {
+		logOrDefault(nil).Error("[scientist] invalid environment", slog.Any("error", err))
+	}
+}
+
+func LoadEnv() error {
+	var firstErr error
+	disabled := make(map[string]bool)
+	percents := make(map[string]float64)
+
+	for _, name := range strings.Split(os.Getenv(envDisable), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			disabled[envName(name)] = true
+		}
+	}
+
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, envPercentPrefix) {
+			continue
+		}
+
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("%s: %w", key, err)
+			}
+			continue
+		}
+
+		percents[envName(strings.TrimPrefix(key, envPercentPrefix))] = percent
+	}
+
+	envConfig.Store(&envSettings{disabled: disabled, percents: percents})
+
+	return firstErr
+}
+
+func envDisabled(name string) bool {
+	s, _ := envConfig.Load().(*envSettings)
+	if s == nil || len(s.disabled) == 0 {
+		return false
+	}
+	return s.disabled[envName(name)]
+}
+
+func envPercent(name string) (float64, bool) {
+	s, _ := envConfig.Load().(*envSettings)
+	if s == nil || len(s.percents) == 0 {
+		return 0, false
+	}
+
+	percent, ok := s.percents[envName(name)]
+	return percent, ok
+}
+
+// envName matches experiment names to environment variable names, which are
+// usually upper case, and can't have dashes or dots.
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,49 @@
+package scientist
+
+import "testing"
+
+func TestLoadEnv(t *testing.T) {
+	t.Cleanup(func() { LoadEnv() })
+	t.Setenv("SCIENTIST_DISABLE", "env-disabled, other")
+	t.Setenv("SCIENTIST_PERCENT_ENV_PERCENT", "0")
+
+	if err := LoadEnv(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	disabled := New("env-disabled")
+	if !disabled.Disabled() {
+		t.Errorf("expected experiment to be disabled")
+	}
+
+	if New("env-enabled").Disabled() {
+		t.Errorf("expected experiment to be enabled")
+	}
+
+	e := New("env.percent")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		t.Errorf("did not expect candidate to run")
+		return 1, nil
+	})
+
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+}
+
+func TestLoadEnvErrors(t *testing.T) {
+	t.Cleanup(func() { LoadEnv() })
+	t.Setenv("SCIENTIST_PERCENT_ENV_VALID", "10")
+	t.Setenv("SCIENTIST_PERCENT_ENV_INVALID", "ten")
+
+	if err := LoadEnv(); err == nil {
+		t.Errorf("expected an error for the invalid percent")
+	}
+
+	if percent, ok := envPercent("env-valid"); !ok || percent != 10 {
+		t.Errorf("Unexpected percent: %v, %v", percent, ok)
+	}
+}
@@ -0,0 +1,129 @@
+// Package errortracker sends scientist errors and mismatches to an error
+// tracking service like Sentry, as events with the experiment, operation, and
+// diff attached. Adapt a service's client by implementing Tracker, mapping
+// levels to the service's own:
+//
+//	levels := map[string]sentry.Level{
+//	  "warning":  sentry.LevelWarning,
+//	  "error":    sentry.LevelError,
+//	  "critical": sentry.LevelFatal,
+//	}
+//	tracker := errortracker.TrackerFunc(func(ev errortracker.Event) {
+//	  event := sentry.NewEvent()
+//	  event.Message = ev.Message
+//	  event.Level = levels[ev.Level]
+//	  event.Tags = ev.Tags
+//	  event.Extra = ev.Extra
+//	  event.Fingerprint = ev.Fingerprint
+//	  sentry.CaptureEvent(event)
+//	})
+//	reporter := errortracker.New(tracker)
+//	experiment.ReportErrors(reporter.ReportErrors)
+//	experiment.OnMismatch(reporter.OnMismatch)
+package errortracker
+
+import (
+	"fmt"
+	"scientist"
+)
+
+// Event is a single error or mismatch.
+type Event struct {
+	Message string
+
+	// Err is the reported error. It's nil for mismatches.
+	Err error
+
+	// Level is "warning", "error", or "critical". Services with other
+	// levels, like Sentry's "fatal", need them mapped.
+	Level string
+
+	// Tags are short values to search and group by: the experiment, and the
+	// operation or behavior when known.
+	Tags map[string]string
+
+	// Extra has longer details, like the diff and the experiment's context.
+	Extra map[string]interface{}
+
+	// Fingerprint groups events for the same problem together.
+	Fingerprint []string
+}
+
+type Tracker interface {
+	Capture(Event)
+}
+
+type TrackerFunc func(Event)
+
+func (f TrackerFunc) Capture(ev Event) {
+	f(ev)
+}
+
+type Reporter struct {
+	Tracker Tracker
+}
+
+func New(t Tracker) *Reporter {
+	return &Reporter{Tracker: t}
+}
+
+// ReportErrors captures an event for each error. Use it with
+// Experiment.ReportErrors.
+func (r *Reporter) ReportErrors(errs ...scientist.ResultError) {
+	for _, err := range errs {
+		tags := map[string]string{
+			"experiment": err.Experiment,
+			"operation":  err.Operation,
+		}
+		fingerprint := []string{"scientist", err.Experiment, err.Operation}
+		if err.Behavior != "" {
+			tags["behavior"] = err.Behavior
+			fingerprint = append(fingerprint, err.Behavior)
+		}
+
+		r.Tracker.Capture(Event{
+			Message:     fmt.Sprintf("[scientist] %s failed in experiment %q: %v", err.Operation, err.Experiment, err.Err),
+			Err:         err.Err,
+			Level:       err.Severity.String(),
+			Tags:        tags,
+			Extra:       map[string]interface{}{"error": err.Error()},
+			Fingerprint: fingerprint,
+		})
+	}
+}
+
+// OnMismatch captures a warning for each mismatched candidate. Use it with
+// Experiment.OnMismatch.
+func (r *Reporter) OnMismatch(res scientist.Result) error {
+	p := res.Payload()
+	for _, o := range res.Mismatched {
+		extra := map[string]interface{}{
+			"diff":      o.Diff,
+			"control":   p.Control,
+			"candidate": o.Payload(),
+		}
+		if len(p.Context) > 0 {
+			extra["context"] = p.Context
+		}
+		if len(p.Data) > 0 {
+			extra["data"] = p.Data
+		}
+
+		tags := map[string]string{
+			"experiment": p.Experiment,
+			"behavior":   o.Name,
+		}
+		if o.Version != "" {
+			tags["version"] = o.Version
+		}
+
+		r.Tracker.Capture(Event{
+			Message:     fmt.Sprintf("[scientist] candidate %q mismatched in experiment %q", o.Name, p.Experiment),
+			Level:       scientist.SeverityWarning.String(),
+			Tags:        tags,
+			Extra:       extra,
+			Fingerprint: []string{"scientist", p.Experiment, "mismatch", o.Name},
+		})
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package errortracker
+
+import (
+	"errors"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+func TestReportErrors(t *testing.T) {
+	var events []Event
+	r := New(TrackerFunc(func(ev Event) {
+		events = append(events, ev)
+	}))
+
+	e := scientist.New("errortracker-errors")
+	e.ReportErrors(r.ReportErrors)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Compare(func(control, candidate interface{}) (bool, error) {
+		return false, errors.New("boom")
+	})
+	e.Run()
+
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+
+	ev := events[0]
+	if ev.Err == nil || ev.Err.Error() != "boom" || ev.Level != "error" {
+		t.Errorf("Unexpected event: %+v", ev)
+	}
+
+	if ev.Tags["experiment"] != "errortracker-errors" || ev.Tags["operation"] != "compare" || ev.Tags["behavior"] != "candidate" {
+		t.Errorf("Unexpected tags: %v", ev.Tags)
+	}
+
+	if strings.Join(ev.Fingerprint, " ") != "scientist errortracker-errors compare candidate" {
+		t.Errorf("Unexpected fingerprint: %v", ev.Fingerprint)
+	}
+}
+
+func TestOnMismatch(t *testing.T) {
+	var events []Event
+	r := New(TrackerFunc(func(ev Event) {
+		events = append(events, ev)
+	}))
+
+	e := scientist.New("errortracker-mismatch")
+	e.AddContext("region", "east")
+	e.OnMismatch(r.OnMismatch)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Behavior("correct", func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Run()
+
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+
+	ev := events[0]
+	if ev.Level != "warning" || ev.Tags["behavior"] != "candidate" || ev.Err != nil {
+		t.Errorf("Unexpected event: %+v", ev)
+	}
+
+	if diff, _ := ev.Extra["diff"].(string); diff == "" {
+		t.Errorf("Expected a diff: %v", ev.Extra)
+	}
+
+	if context, _ := ev.Extra["context"].(map[string]string); context["region"] != "east" {
+		t.Errorf("Unexpected context: %v", ev.Extra["context"])
+	}
+}
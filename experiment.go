@@ -1,122 +1,687 @@
 package scientist
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"hash/fnv"
+	"log/slog"
+	"math/rand"
 	"reflect"
+	"sync"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	ErrorOnMismatches    bool
+	CaptureControlPanics bool
 )
 
-var ErrorOnMismatches bool
+var defaults struct {
+	sync.RWMutex
+	fns []func(e *Experiment)
+}
+
+func SetDefaults(fns ...func(e *Experiment)) {
+	defaults.Lock()
+	defaults.fns = fns
+	defaults.Unlock()
+}
 
 func New(name string) *Experiment {
-	return &Experiment{
-		Name:              name,
-		Context:           make(map[string]string),
-		ErrorOnMismatches: ErrorOnMismatches,
-		behaviors:         make(map[string]behaviorFunc),
-		comparator:        defaultComparator,
-		runcheck:          defaultRunCheck,
-		publisher:         defaultPublisher,
-		errorReporter:     defaultErrorReporter,
-		beforeRun:         defaultBeforeRun,
-		cleaner:           defaultCleaner,
+	e := &Experiment{
+		Name:                 name,
+		Context:              make(map[string]string),
+		ErrorOnMismatches:    ErrorOnMismatches,
+		CaptureControlPanics: CaptureControlPanics,
+		percent:              100,
+		publishPercent:       100,
+		behaviors:            make(map[string]behaviorFunc),
+		validation:           &validation{},
+		comparator:           defaultComparator,
+		errorComparator:      defaultErrorComparator,
+		runcheck:             defaultRunCheck,
+		publisher:            defaultPublisher,
+		errorReporter:        defaultErrorReporter,
+		classifier:           DefaultSeverity,
+		cleaner:              defaultCleaner,
+	}
+	e.state = register(e)
+
+	defaults.RLock()
+	fns := defaults.fns
+	defaults.RUnlock()
+
+	for _, fn := range fns {
+		fn(e)
 	}
+
+	return e
 }
 
-type behaviorFunc func() (value interface{}, err error)
+type runOptions struct {
+	ctx   context.Context
+	key   string
+	keyed bool
+	data  map[string]interface{}
+
+	// variant runs only the behavior the key is assigned to.
+	variant bool
+}
+
+type behaviorFunc func(ctx context.Context) (value interface{}, err error)
 
 type Experiment struct {
-	Name              string
-	Context           map[string]string
-	ErrorOnMismatches bool
-	behaviors         map[string]behaviorFunc
-	ignores           []func(control, candidate interface{}) (bool, error)
-	comparator        func(control, candidate interface{}) (bool, error)
-	runcheck          func() (bool, error)
-	publisher         func(Result) error
-	errorReporter     func(...ResultError)
-	beforeRun         func() error
-	cleaner           func(interface{}) (interface{}, error)
+	Name                  string
+	Context               map[string]string
+	ErrorOnMismatches     bool
+	CaptureControlPanics  bool
+	state                 *experimentState
+	validation            *validation
+	concurrent            bool
+	controlFirst          bool
+	async                 bool
+	asyncComparison       bool
+	publishSkipped        bool
+	timeout               time.Duration
+	timeouts              map[string]time.Duration
+	versions              map[string]string
+	promotion             *promotion
+	faults                *Faults
+	hashValues            bool
+	streams               bool
+	cloner                func(interface{}) interface{}
+	percent               float64
+	publishPercent        float64
+	dedupWindow           time.Duration
+	expiresAt             time.Time
+	behaviors             map[string]behaviorFunc
+	lifecycles            map[string]Behavior
+	ignores               []func(control, candidate *Observation) (bool, string, error)
+	comparator            func(control, candidate interface{}) (bool, error)
+	cmpOptions            []cmp.Option
+	errorComparator       func(control, candidate error) (bool, error)
+	observationComparator func(control, candidate *Observation) (bool, error)
+	runcheck              func() (bool, error)
+	aborter               func(name string)
+	publisher             func(Result) error
+	errorReporter         func(...ResultError)
+	classifier            func(ResultError) Severity
+	beforeRuns            []func() error
+	afterRuns             []func(Result) error
+	mismatchHandlers      []func(Result) error
+	cleaner               func(interface{}) (interface{}, error)
+	scrubber              func(interface{}) interface{}
+	maxValueSize          int
+	breaker               *breakerConfig
+	adaptive              *adaptiveConfig
+	rateLimit             *rateLimit
+	skipChecks            []func() bool
+	measureAllocs         bool
+	maxSlowdown           float64
+	slowFactor            float64
+	deadlinePolicy        DeadlinePolicy
+	candidateTimes        int
+	controlTimes          int
+	warmups               int
+	sandbox               func(ctx context.Context, run func(ctx context.Context) error) error
+	wrappers              []func(name string, next func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error)
 }
 
 func (e *Experiment) Use(fn func() (interface{}, error)) {
 	e.Behavior(controlBehavior, fn)
 }
 
+func (e *Experiment) UseContext(fn func(ctx context.Context) (interface{}, error)) {
+	e.BehaviorContext(controlBehavior, fn)
+}
+
 func (e *Experiment) Try(fn func() (interface{}, error)) {
 	e.Behavior(candidateBehavior, fn)
 }
 
+func (e *Experiment) TryContext(fn func(ctx context.Context) (interface{}, error)) {
+	e.BehaviorContext(candidateBehavior, fn)
+}
+
 func (e *Experiment) Behavior(name string, fn func() (interface{}, error)) {
+	if !e.behavior(name, fn) {
+		return
+	}
+
+	e.behaviors[name] = func(ctx context.Context) (interface{}, error) {
+		return fn()
+	}
+}
+
+func (e *Experiment) BehaviorContext(name string, fn func(ctx context.Context) (interface{}, error)) {
+	if !e.behavior(name, fn) {
+		return
+	}
+
 	e.behaviors[name] = fn
 }
 
+// TryWithTimeout adds a candidate with its own timeout, overriding the one
+// given to EnableConcurrency or EnableControlFirst.
+func (e *Experiment) TryWithTimeout(name string, timeout time.Duration, fn func(ctx context.Context) (interface{}, error)) {
+	if !e.behavior(name, fn) {
+		return
+	}
+
+	if e.timeouts == nil {
+		e.timeouts = make(map[string]time.Duration)
+	}
+	e.behaviors[name] = fn
+	e.timeouts[name] = timeout
+}
+
+// CandidateVersion records the version of a behavior's code, like a git SHA or
+// build tag. It's set on the behavior's observations and published with them,
+// so mismatches can be tied to a version.
+func (e *Experiment) CandidateVersion(name, version string) {
+	if !e.configurable("CandidateVersion") {
+		return
+	}
+
+	if e.versions == nil {
+		e.versions = make(map[string]string)
+	}
+	e.versions[name] = version
+}
+
+func (e *Experiment) EnableConcurrency(timeout time.Duration) {
+	if !e.configurable("EnableConcurrency") {
+		return
+	}
+
+	e.concurrent = true
+	e.timeout = timeout
+}
+
+func (e *Experiment) EnableControlFirst(timeout time.Duration) {
+	if !e.configurable("EnableControlFirst") {
+		return
+	}
+
+	e.concurrent = true
+	e.controlFirst = true
+	e.timeout = timeout
+}
+
+func (e *Experiment) RunCandidateTimes(n int) {
+	if !e.configurable("RunCandidateTimes") {
+		return
+	}
+
+	e.candidateTimes = n
+}
+
+// RunControlTimes runs the control n times when the candidates run, and marks
+// it Unstable if it doesn't return the same value every time, so its
+// nondeterminism isn't blamed on the candidates. Run returns the first value.
+// Only use it for controls that are safe to repeat.
+func (e *Experiment) RunControlTimes(n int) {
+	if !e.configurable("RunControlTimes") {
+		return
+	}
+
+	e.controlTimes = n
+}
+
+func (e *Experiment) MaxSlowdown(factor float64) {
+	if !e.configurable("MaxSlowdown") {
+		return
+	}
+
+	e.maxSlowdown = factor
+}
+
+func (e *Experiment) Abort(fn func(name string)) {
+	if !e.callback("Abort", fn) {
+		return
+	}
+
+	e.aborter = fn
+}
+
+func (e *Experiment) EnableAsync() {
+	if !e.configurable("EnableAsync") {
+		return
+	}
+
+	e.async = true
+}
+
+// EnableAsyncComparison runs the candidates during Run, but compares, ignores,
+// cleans, and publishes them on a background goroutine, so expensive
+// comparisons don't add latency. AfterRun callbacks run in the background
+// too, and ErrorOnMismatches has no effect.
+func (e *Experiment) EnableAsyncComparison() {
+	if !e.configurable("EnableAsyncComparison") {
+		return
+	}
+
+	e.asyncComparison = true
+}
+
 func (e *Experiment) Compare(fn func(control, candidate interface{}) (bool, error)) {
+	if !e.callback("Compare", fn) {
+		return
+	}
+
 	e.comparator = fn
 }
 
+func (e *Experiment) CompareErrors(fn func(control, candidate error) (bool, error)) {
+	if !e.callback("CompareErrors", fn) {
+		return
+	}
+
+	e.errorComparator = fn
+}
+
+func (e *Experiment) CompareObservations(fn func(control, candidate *Observation) (bool, error)) {
+	if !e.callback("CompareObservations", fn) {
+		return
+	}
+
+	e.observationComparator = fn
+}
+
 func (e *Experiment) Clean(fn func(v interface{}) (interface{}, error)) {
+	if !e.callback("Clean", fn) {
+		return
+	}
+
 	e.cleaner = fn
 }
 
+func (e *Experiment) Scrub(fn func(v interface{}) interface{}) {
+	if !e.callback("Scrub", fn) {
+		return
+	}
+
+	e.scrubber = fn
+}
+
+func (e *Experiment) TruncateValues(max int) {
+	if !e.configurable("TruncateValues") {
+		return
+	}
+
+	e.maxValueSize = max
+}
+
 func (e *Experiment) Ignore(fn func(control, candidate interface{}) (bool, error)) {
+	if !e.callback("Ignore", fn) {
+		return
+	}
+
+	e.IgnoreObservations(func(control, candidate *Observation) (bool, error) {
+		return fn(control.Value, candidate.Value)
+	})
+}
+
+func (e *Experiment) IgnoreObservations(fn func(control, candidate *Observation) (bool, error)) {
+	if !e.callback("IgnoreObservations", fn) {
+		return
+	}
+
+	e.IgnoreWithReason(func(control, candidate *Observation) (bool, string, error) {
+		ok, err := fn(control, candidate)
+		return ok, "", err
+	})
+}
+
+func (e *Experiment) IgnoreWithReason(fn func(control, candidate *Observation) (ignored bool, reason string, err error)) {
+	if !e.callback("IgnoreWithReason", fn) {
+		return
+	}
+
 	e.ignores = append(e.ignores, fn)
 }
 
 func (e *Experiment) RunIf(fn func() (bool, error)) {
+	if !e.callback("RunIf", fn) {
+		return
+	}
+
 	e.runcheck = fn
 }
 
+func (e *Experiment) RunPercent(percent float64) {
+	if !e.configurable("RunPercent") {
+		return
+	}
+
+	e.percent = percent
+}
+
 func (e *Experiment) BeforeRun(fn func() error) {
-	e.beforeRun = fn
+	if !e.callback("BeforeRun", fn) {
+		return
+	}
+
+	e.beforeRuns = append(e.beforeRuns, fn)
+}
+
+func (e *Experiment) AfterRun(fn func(Result) error) {
+	e.afterRun(fn)
+}
+
+// afterRun adds an AfterRun callback, and returns whether it was added.
+func (e *Experiment) afterRun(fn func(Result) error) bool {
+	if !e.callback("AfterRun", fn) {
+		return false
+	}
+
+	e.afterRuns = append(e.afterRuns, fn)
+	return true
 }
 
 func (e *Experiment) Publish(fn func(Result) error) {
+	if !e.callback("Publish", fn) {
+		return
+	}
+
 	e.publisher = fn
 }
 
-func (e *Experiment) ReportErrors(fn func(...ResultError)) {
-	e.errorReporter = fn
+func (e *Experiment) OnMismatch(fn func(Result) error) {
+	if !e.callback("OnMismatch", fn) {
+		return
+	}
+
+	e.mismatchHandlers = append(e.mismatchHandlers, fn)
+}
+
+func (e *Experiment) SampleMatched(percent float64) {
+	if !e.configurable("SampleMatched") {
+		return
+	}
+
+	e.publishPercent = percent
+}
+
+// AddContext adds a value to the experiment's Context, formatted with
+// fmt.Sprint, so results can be sliced by it.
+func (e *Experiment) AddContext(key string, value interface{}) {
+	if !e.configurable("AddContext") {
+		return
+	}
+
+	e.Context[key] = fmt.Sprint(value)
+}
+
+// ReportErrors replaces the experiment's error reporters. Each one gets every
+// error, and one that panics doesn't stop the others.
+func (e *Experiment) ReportErrors(fns ...func(...ResultError)) {
+	if !e.configurable("ReportErrors") {
+		return
+	}
+
+	for _, fn := range fns {
+		if fn == nil {
+			e.invalid(fmt.Errorf("ReportErrors called with a nil callback"))
+			return
+		}
+	}
+
+	e.errorReporter = func(errs ...ResultError) {
+		for _, fn := range fns {
+			callReporter(fn, errs)
+		}
+	}
+}
+
+func callReporter(fn func(...ResultError), errs []ResultError) {
+	defer func() {
+		if p := recover(); p != nil {
+			logOrDefault(nil).Error("[scientist] error reporter panicked", slog.Any("panic", p))
+		}
+	}()
+
+	fn(errs...)
 }
 
 func (e *Experiment) Run() (interface{}, error) {
 	return e.RunBehavior(controlBehavior)
 }
 
+func (e *Experiment) RunContext(ctx context.Context) (interface{}, error) {
+	return e.RunBehaviorContext(ctx, controlBehavior)
+}
+
+// Conduct runs the experiment like RunContext, and also returns its Result so
+// callers can inspect it without a publisher. When the candidates don't run,
+// or run in the background with EnableAsync, the Result only has the control.
+func (e *Experiment) Conduct(ctx context.Context) (interface{}, Result, error) {
+	started := time.Now()
+	opts := runOptions{ctx: ctx}
+	v, r, err := e.conduct(controlBehavior, opts)
+	if r.Control == nil {
+		r = newResult(e, opts)
+		r.Control = &Observation{
+			Experiment: e,
+			Name:       controlBehavior,
+			Started:    started,
+			Runtime:    time.Since(started),
+			Value:      v,
+			Err:        err,
+		}
+		r.Observations = []*Observation{r.Control}
+	}
+
+	return v, r, err
+}
+
+func (e *Experiment) RunWithKey(key string) (interface{}, error) {
+	return e.run(controlBehavior, runOptions{ctx: context.Background(), key: key, keyed: true})
+}
+
+// RunWithContextData runs the experiment and attaches data, like a request ID,
+// to this run's Result only.
+func (e *Experiment) RunWithContextData(data map[string]interface{}) (interface{}, error) {
+	return e.RunContextWithContextData(context.Background(), data)
+}
+
+func (e *Experiment) RunContextWithContextData(ctx context.Context, data map[string]interface{}) (interface{}, error) {
+	return e.run(controlBehavior, runOptions{ctx: ctx, data: data})
+}
+
 func (e *Experiment) RunBehavior(name string) (interface{}, error) {
-	enabled, err := e.runcheck()
+	return e.RunBehaviorContext(context.Background(), name)
+}
+
+func (e *Experiment) RunBehaviorContext(ctx context.Context, name string) (interface{}, error) {
+	return e.run(name, runOptions{ctx: ctx})
+}
+
+func (e *Experiment) run(name string, opts runOptions) (interface{}, error) {
+	v, _, err := e.conduct(name, opts)
+	return v, err
+}
+
+// conduct runs the experiment, and returns the Result along with the value.
+// When the candidates don't run, the Result is empty.
+func (e *Experiment) conduct(name string, opts runOptions) (interface{}, Result, error) {
+	if err := e.seal(name); err != nil {
+		e.report(e.resultErr("validate", err))
+		return nil, Result{}, err
+	}
+
+	// Most runs of a sampled experiment skip their candidates, so the cheap
+	// checks come first, and skipped runs go straight to the control.
+	hasCandidates := len(e.behaviors) > 1
+	disabled := hasCandidates && e.Disabled()
+	inPercent := disabled || e.inPercent(opts)
+	if !disabled && !inPercent && !e.publishSkipped && e.promotion == nil {
+		v, err := runControl(e, name, opts.ctx)
+		return v, Result{}, err
+	}
+
+	e = e.configured()
+
+	var endTrace func()
+	opts.ctx, endTrace = traceRun(opts.ctx, e)
+	defer endTrace()
+
+	if disabled {
+		reason := SkipDisabled
+		if now := time.Now(); e.expired(now) {
+			reason = SkipExpired
+			e.noticeExpired(now)
+		}
+
+		r := runSkipped(e, name, opts, reason)
+		return r.Control.returnValue(), r, r.Control.Err
+	}
+
+	name = e.promoted(name, opts)
+	skip, err := e.skipReason(inPercent)
 	if err != nil {
-		enabled = true
-		e.errorReporter(e.resultErr("run_if", err))
-		return nil, err
+		e.report(e.resultErr("run_if", err))
+		return nil, Result{}, err
 	}
 
-	if enabled && len(e.behaviors) > 1 {
-		r := Run(e, name)
+	if hasCandidates && skip != "" && e.publishSkipped {
+		r := runSkipped(e, name, opts, skip)
+		return r.Control.returnValue(), r, r.Control.Err
+	}
 
-		if r.Control.Err == nil && e.ErrorOnMismatches && r.IsMismatched() {
-			return nil, MismatchError{r}
+	if hasCandidates && skip == "" {
+		var r Result
+		switch {
+		case !e.allowRun():
+			r = runSkipped(e, name, opts, SkipRateLimited)
+		case opts.variant:
+			r = runVariant(e, name, opts)
+		case e.streams:
+			r = runStreams(e, name, opts)
+		case e.async:
+			r = runAsync(e, name, opts)
+		case e.asyncComparison:
+			r = runAsyncComparison(e, name, opts)
+		default:
+			r = run(e, name, opts)
+			if r.Control.Err == nil && e.ErrorOnMismatches && r.IsMismatched() {
+				return nil, r, newMismatchError(r)
+			}
 		}
 
-		return r.Control.Value, r.Control.Err
+		return r.Control.returnValue(), r, r.Control.Err
 	}
 
+	v, err := runControl(e, name, opts.ctx)
+	return v, Result{}, err
+}
+
+// runControl calls only the named behavior, without observing it, so runs
+// that skip the candidates cost next to nothing.
+func runControl(e *Experiment, name string, ctx context.Context) (interface{}, error) {
 	behavior, ok := e.behaviors[name]
 	if !ok {
 		return nil, behaviorNotFound(e, name)
 	}
 
-	return behavior()
+	ctx = controlContext(ctx, e)
+	tearDown, err := setUp(ctx, e, name, e.CaptureControlPanics)
+	if err != nil {
+		return nil, err
+	}
+	defer tearDown()
+
+	behavior = wrapped(e, name, behavior)
+	if e.CaptureControlPanics {
+		return callBehavior(ctx, behavior)
+	}
+
+	return behavior(ctx)
+}
+
+// inPercent returns whether this run falls within the experiment's
+// RunPercent, as overridden by Configure, AdaptiveRollout, or the environment.
+func (e *Experiment) inPercent(opts runOptions) bool {
+	percent := e.percent
+	if s := e.settings(); s != nil && s.Percent != nil {
+		percent = *s.Percent
+	}
+
+	if e.adaptive != nil {
+		percent = e.state.adaptive.current(e.adaptive)
+	}
+
+	if p, ok := envPercent(e.Name); ok {
+		percent = p
+	}
+
+	if percent >= 100 {
+		return true
+	}
+
+	if opts.keyed {
+		return bucket(e.Name, opts.key) < percent
+	}
+	return rand.Float64()*100 < percent
+}
+
+// skipReason returns why this run shouldn't run the candidates, or an empty
+// string if it should.
+func (e *Experiment) skipReason(inPercent bool) (string, error) {
+	if !inPercent {
+		return SkipPercent, nil
+	}
+
+	ok, err := e.runcheck()
+	if err != nil || ok {
+		return "", err
+	}
+	return SkipRunIf, nil
+}
+
+func (e *Experiment) sampled(r Result) bool {
+	if e.publishPercent >= 100 || r.IsMismatched() || r.IsIgnored() {
+		return true
+	}
+
+	return rand.Float64()*100 < e.publishPercent
+}
+
+func (e *Experiment) publishedValue(v interface{}) interface{} {
+	if e.scrubber != nil {
+		v = e.scrubber(v)
+	}
+
+	if e.maxValueSize > 0 {
+		v = truncate(v, e.maxValueSize)
+	}
+
+	return v
+}
+
+func (e *Experiment) ReportError(operation string, err error) {
+	e.report(e.resultErr(operation, err))
 }
 
 func (e *Experiment) resultErr(name string, err error) ResultError {
-	return ResultError{name, e.Name, err}
+	return e.classify(ResultError{Operation: name, Experiment: e.Name, Err: err})
+}
+
+// behaviorErr is like resultErr, for errors caused by a single behavior.
+func (e *Experiment) behaviorErr(name, behavior string, err error) ResultError {
+	return e.classify(ResultError{Operation: name, Experiment: e.Name, Behavior: behavior, Err: err})
 }
 
 func defaultComparator(candidate, control interface{}) (bool, error) {
 	return reflect.DeepEqual(candidate, control), nil
 }
 
+func defaultErrorComparator(control, candidate error) (bool, error) {
+	return control.Error() == candidate.Error(), nil
+}
+
 func defaultRunCheck() (bool, error) {
 	return true, nil
 }
@@ -130,11 +695,15 @@ func defaultPublisher(r Result) error {
 }
 
 func defaultErrorReporter(errs ...ResultError) {
-	for _, err := range errs {
-		fmt.Fprintf(os.Stderr, "[scientist] error during %q for %q experiment: (%T) %v\n", err.Operation, err.Experiment, err.Err, err.Err)
-	}
+	LogErrors(nil)(errs...)
 }
 
-func defaultBeforeRun() error {
-	return nil
+// bucket deterministically places a key between 0 and 100, so the same key is
+// always sampled the same way for an experiment.
+func bucket(experiment, key string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(experiment))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return float64(h.Sum32()%10000) / 100
 }
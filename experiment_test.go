@@ -1,9 +1,14 @@
 package scientist
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestExperimentMatch(t *testing.T) {
@@ -162,6 +167,97 @@ func TestExperimentRunBefore(t *testing.T) {
 	}
 }
 
+func TestExperimentBeforeAndAfterRun(t *testing.T) {
+	var calls []string
+
+	e := New("run")
+	e.Use(func() (interface{}, error) {
+		calls = append(calls, "control")
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		calls = append(calls, "candidate")
+		return 2, nil
+	})
+
+	e.BeforeRun(func() error {
+		calls = append(calls, "before 1")
+		return nil
+	})
+	e.BeforeRun(func() error {
+		calls = append(calls, "before 2")
+		return fmt.Errorf("fixture")
+	})
+	e.AfterRun(func(r Result) error {
+		calls = append(calls, "after")
+		if !r.IsMismatched() {
+			t.Errorf("expected AfterRun to see the mismatch")
+		}
+		return nil
+	})
+
+	e.SampleMatched(0)
+	e.Publish(func(r Result) error {
+		calls = append(calls, "publish")
+		return nil
+	})
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	if _, err := e.Run(); err != nil {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	if len(calls) != 6 || calls[0] != "before 1" || calls[1] != "before 2" || calls[4] != "after" || calls[5] != "publish" {
+		t.Errorf("Unexpected calls: %v", calls)
+	}
+
+	if len(reported) != 1 || reported[0].Operation != "before_run" {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+}
+
+func TestExperimentAfterRunWithoutPublish(t *testing.T) {
+	after := false
+
+	e := New("run")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	e.SampleMatched(0)
+	e.Publish(func(r Result) error {
+		t.Errorf("did not expect matched result to publish")
+		return nil
+	})
+
+	e.AfterRun(func(r Result) error {
+		after = true
+		return fmt.Errorf("cleanup")
+	})
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	e.Run()
+
+	if !after {
+		t.Errorf("expected AfterRun callback to run")
+	}
+
+	if len(reported) != 1 || reported[0].Operation != "after_run" {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+}
+
 func TestExperimentDisabledRunBefore(t *testing.T) {
 	runIf := false
 
@@ -393,3 +489,1094 @@ func TestExperimentSkipCompareSameErrors(t *testing.T) {
 		t.Errorf("results never published")
 	}
 }
+
+func TestExperimentContextBehaviors(t *testing.T) {
+	e := New("context")
+	e.UseContext(func(ctx context.Context) (interface{}, error) {
+		if ctx == nil {
+			t.Errorf("expected a control context")
+		}
+		return 1, nil
+	})
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		return 1, ctx.Err()
+	})
+
+	published := false
+	e.Publish(func(r Result) error {
+		published = true
+
+		if !r.IsMatched() {
+			t.Errorf("not matched")
+		}
+
+		return nil
+	})
+
+	v, err := e.Run()
+	if v != 1 {
+		t.Errorf("Unexpected control value: %d", v)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	if !published {
+		t.Errorf("expected Publish callback to run")
+	}
+}
+
+func TestExperimentConcurrentTimeout(t *testing.T) {
+	cancelled := make(chan error, 1)
+
+	e := New("timeout")
+	e.EnableConcurrency(10 * time.Millisecond)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		cancelled <- ctx.Err()
+		return 1, nil
+	})
+	e.Behavior("fast", func() (interface{}, error) {
+		return 1, nil
+	})
+
+	published := false
+	e.Publish(func(r Result) error {
+		published = true
+
+		assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate"})
+		if err := r.Mismatched[0].Err; err != context.DeadlineExceeded {
+			t.Errorf("Unexpected candidate error: %v", err)
+		}
+
+		assertObservationNames(t, "timed out", r.TimedOut, []string{"candidate"})
+		if o := r.TimedOut[0]; o.Started.IsZero() || o.Runtime < 10*time.Millisecond {
+			t.Errorf("Expected a partial observation, got started %v after %v", o.Started, o.Runtime)
+		}
+
+		if p := r.Payload(); !p.Mismatched || len(p.TimedOut) != 1 || !p.Candidates[0].TimedOut && !p.Candidates[1].TimedOut {
+			t.Errorf("Unexpected payload: %+v", p)
+		}
+
+		return nil
+	})
+
+	v, err := e.Run()
+	if v != 1 {
+		t.Errorf("Unexpected control value: %d", v)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	if !published {
+		t.Errorf("expected Publish callback to run")
+	}
+
+	select {
+	case err := <-cancelled:
+		if err != context.DeadlineExceeded {
+			t.Errorf("Unexpected candidate context error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("candidate context was never cancelled")
+	}
+}
+
+func TestExperimentCandidatePanic(t *testing.T) {
+	e := New("panic")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		panic("candidate")
+	})
+
+	published := false
+	e.Publish(func(r Result) error {
+		published = true
+
+		assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate"})
+		pe, ok := r.Mismatched[0].Err.(PanicError)
+		if !ok {
+			t.Fatalf("Unexpected candidate error: %v", r.Mismatched[0].Err)
+		}
+
+		if pe.Value != "candidate" {
+			t.Errorf("Unexpected panic value: %v", pe.Value)
+		}
+
+		if len(pe.Stack) == 0 {
+			t.Errorf("expected a stack trace")
+		}
+
+		return nil
+	})
+
+	v, err := e.Run()
+	if v != 1 {
+		t.Errorf("Unexpected control value: %d", v)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	if !published {
+		t.Errorf("expected Publish callback to run")
+	}
+}
+
+func TestExperimentControlPanic(t *testing.T) {
+	for _, concurrent := range []bool{false, true} {
+		e := New("panic")
+		if concurrent {
+			e.EnableConcurrency(0)
+		}
+		e.Use(func() (interface{}, error) {
+			panic("control")
+		})
+		e.Try(func() (interface{}, error) {
+			return 1, nil
+		})
+
+		e.Publish(func(r Result) error {
+			t.Errorf("did not expect to publish")
+			return nil
+		})
+
+		func() {
+			defer func() {
+				if p := recover(); p != "control" {
+					t.Errorf("Unexpected panic (concurrent=%t): %v", concurrent, p)
+				}
+			}()
+			e.Run()
+		}()
+	}
+}
+
+func TestExperimentCaptureControlPanics(t *testing.T) {
+	e := New("panic")
+	e.CaptureControlPanics = true
+	e.Use(func() (interface{}, error) {
+		panic("control")
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	v, err := e.Run()
+	if v != nil {
+		t.Errorf("Unexpected control value: %v", v)
+	}
+
+	if pe, ok := err.(PanicError); !ok || pe.Value != "control" {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+}
+
+func TestExperimentRunPercent(t *testing.T) {
+	for _, percent := range []float64{0, 100} {
+		e := New("percent")
+		e.RunPercent(percent)
+		e.Use(func() (interface{}, error) {
+			return 1, nil
+		})
+
+		tried := false
+		e.Try(func() (interface{}, error) {
+			tried = true
+			return 1, nil
+		})
+
+		v, err := e.Run()
+		if v != 1 {
+			t.Errorf("Unexpected control value: %d", v)
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected control error: %v", err)
+		}
+
+		if expected := percent == 100; tried != expected {
+			t.Errorf("Expected candidate to run at %v%%: %t", percent, expected)
+		}
+	}
+}
+
+func TestExperimentRunWithKey(t *testing.T) {
+	enabled := 0
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		tried := 0
+		for j := 0; j < 3; j++ {
+			e := New("keyed")
+			e.RunPercent(50)
+			e.Use(func() (interface{}, error) {
+				return 1, nil
+			})
+			e.Try(func() (interface{}, error) {
+				tried++
+				return 1, nil
+			})
+			e.Publish(func(r Result) error {
+				if r.Key != key {
+					t.Errorf("Unexpected result key: %q", r.Key)
+				}
+
+				if r.Bucket >= 50 {
+					t.Errorf("Unexpected bucket for %q: %v", key, r.Bucket)
+				}
+
+				return nil
+			})
+
+			if v, err := e.RunWithKey(key); v != 1 || err != nil {
+				t.Errorf("Unexpected control result: %v, %v", v, err)
+			}
+		}
+
+		switch tried {
+		case 0:
+		case 3:
+			enabled++
+		default:
+			t.Errorf("Key %q was enabled %d of 3 times", key, tried)
+		}
+	}
+
+	if enabled == 0 || enabled == 100 {
+		t.Errorf("Expected some keys to be sampled, got %d of 100", enabled)
+	}
+}
+
+func TestExperimentAsync(t *testing.T) {
+	release := make(chan struct{})
+	published := make(chan Result, 1)
+
+	e := New("async")
+	e.EnableAsync()
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		<-release
+		return 2, nil
+	})
+	e.Publish(func(r Result) error {
+		published <- r
+		return nil
+	})
+
+	v, err := e.Run()
+	if v != 1 {
+		t.Errorf("Unexpected control value: %d", v)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	select {
+	case <-published:
+		t.Fatalf("published before the candidate finished")
+	default:
+	}
+
+	close(release)
+
+	select {
+	case r := <-published:
+		if r.Control.Value != 1 {
+			t.Errorf("Unexpected control observation: %v", r.Control.Value)
+		}
+		assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate"})
+	case <-time.After(time.Second):
+		t.Errorf("expected Publish callback to run")
+	}
+}
+
+func TestExperimentAsyncComparison(t *testing.T) {
+	release := make(chan struct{})
+	published := make(chan Result, 1)
+	ran := false
+
+	e := New("async-comparison")
+	e.EnableAsyncComparison()
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		ran = true
+		return 2, nil
+	})
+	e.Compare(func(control, candidate interface{}) (bool, error) {
+		<-release
+		return control == candidate, nil
+	})
+	e.Publish(func(r Result) error {
+		published <- r
+		return nil
+	})
+
+	v, r, err := e.Conduct(context.Background())
+	if v != 1 || err != nil {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+
+	if !ran {
+		t.Errorf("Expected the candidate to run before returning")
+	}
+
+	if r.Control == nil || len(r.Candidates) != 0 {
+		t.Errorf("Expected a result with only the control: %+v", r)
+	}
+
+	close(release)
+
+	select {
+	case r := <-published:
+		assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate"})
+	case <-time.After(time.Second):
+		t.Errorf("expected Publish callback to run")
+	}
+}
+
+func TestExperimentConcurrentAbort(t *testing.T) {
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	e := New("abort")
+	e.EnableConcurrency(10 * time.Millisecond)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		// ignores its context, and only stops when aborted
+		<-stop
+		close(stopped)
+		return 1, nil
+	})
+
+	aborted := []string{}
+	e.Abort(func(name string) {
+		aborted = append(aborted, name)
+		close(stop)
+	})
+
+	v, err := e.Run()
+	if v != 1 {
+		t.Errorf("Unexpected control value: %d", v)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	if len(aborted) != 1 || aborted[0] != "candidate" {
+		t.Errorf("Unexpected aborted candidates: %v", aborted)
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Errorf("candidate was never aborted")
+	}
+}
+
+func TestExperimentRunContext(t *testing.T) {
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "request")
+
+	e := New("context")
+	e.UseContext(func(ctx context.Context) (interface{}, error) {
+		return ctx.Value(key{}), nil
+	})
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		return ctx.Value(key{}), nil
+	})
+
+	published := false
+	e.Publish(func(r Result) error {
+		published = true
+
+		if v := r.Context().Value(key{}); v != "request" {
+			t.Errorf("Unexpected result context value: %v", v)
+		}
+
+		if !r.IsMatched() {
+			t.Errorf("not matched")
+		}
+
+		return nil
+	})
+
+	v, err := e.RunContext(ctx)
+	if v != "request" {
+		t.Errorf("Unexpected control value: %v", v)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	if !published {
+		t.Errorf("expected Publish callback to run")
+	}
+}
+
+func TestExperimentCompareErrors(t *testing.T) {
+	notFound := errors.New("not found")
+
+	e := New("errors")
+	e.Use(func() (interface{}, error) {
+		return nil, fmt.Errorf("control at %v: %w", time.Now(), notFound)
+	})
+	e.Try(func() (interface{}, error) {
+		return nil, fmt.Errorf("candidate: %w", notFound)
+	})
+	e.Behavior("other", func() (interface{}, error) {
+		return nil, errors.New("other")
+	})
+	e.CompareErrors(func(control, candidate error) (bool, error) {
+		return errors.Is(control, notFound) && errors.Is(candidate, notFound), nil
+	})
+
+	r := Run(e, "control")
+	if len(r.Errors) != 0 {
+		t.Errorf("Unexpected experiment errors: %v", r.Errors)
+	}
+
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"other"})
+}
+
+func TestExperimentCompareObservations(t *testing.T) {
+	notFound := errors.New("not found")
+
+	e := New("observations")
+	e.Use(func() (interface{}, error) {
+		return 1, notFound
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, notFound
+	})
+	e.Behavior("found", func() (interface{}, error) {
+		return 1, nil
+	})
+	e.CompareObservations(func(control, candidate *Observation) (bool, error) {
+		if errors.Is(control.Err, notFound) && errors.Is(candidate.Err, notFound) {
+			return true, nil
+		}
+		return control.Value == candidate.Value && control.Err == candidate.Err, nil
+	})
+
+	r := Run(e, "control")
+	if len(r.Errors) != 0 {
+		t.Errorf("Unexpected experiment errors: %v", r.Errors)
+	}
+
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"found"})
+}
+
+func TestExperimentSetDefaults(t *testing.T) {
+	defer SetDefaults()
+
+	var defaultPublished, published []string
+	SetDefaults(func(e *Experiment) {
+		e.Publish(func(r Result) error {
+			defaultPublished = append(defaultPublished, r.Experiment.Name)
+			return nil
+		})
+		e.Compare(func(control, candidate interface{}) (bool, error) {
+			return true, nil
+		})
+	})
+
+	e := New("defaults")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Run()
+
+	overridden := New("overridden")
+	overridden.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	overridden.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	overridden.Publish(func(r Result) error {
+		if !r.IsMatched() {
+			t.Errorf("expected default comparator to match")
+		}
+		published = append(published, r.Experiment.Name)
+		return nil
+	})
+	overridden.Run()
+
+	if len(defaultPublished) != 1 || defaultPublished[0] != "defaults" {
+		t.Errorf("Unexpected default publishes: %v", defaultPublished)
+	}
+
+	if len(published) != 1 || published[0] != "overridden" {
+		t.Errorf("Unexpected publishes: %v", published)
+	}
+
+	SetDefaults()
+	reset := New("reset")
+	reset.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	reset.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	reset.Run()
+
+	if len(defaultPublished) != 1 {
+		t.Errorf("Unexpected default publishes: %v", defaultPublished)
+	}
+}
+
+func TestExperimentRunFromGoroutines(t *testing.T) {
+	for _, mode := range []string{"sequential", "concurrent", "async"} {
+		e := basicExperiment()
+		e.Context["mode"] = mode
+		e.Ignore(func(control, candidate interface{}) (bool, error) {
+			return candidate == 3, nil
+		})
+
+		var published int32
+		e.Publish(func(r Result) error {
+			atomic.AddInt32(&published, 1)
+			return nil
+		})
+
+		switch mode {
+		case "concurrent":
+			e.EnableConcurrency(time.Second)
+		case "async":
+			e.EnableAsync()
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if v, err := e.Run(); v != 1 || err != nil {
+					t.Errorf("Unexpected %s control result: %v, %v", mode, v, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for atomic.LoadInt32(&published) < 20 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+
+		if n := atomic.LoadInt32(&published); n != 20 {
+			t.Errorf("Unexpected %s publishes: %d", mode, n)
+		}
+	}
+}
+
+func TestExperimentControlFirst(t *testing.T) {
+	controlDone := make(chan struct{})
+	started := make(chan string, 2)
+	release := make(chan struct{})
+
+	e := New("control-first")
+	e.EnableControlFirst(time.Second)
+	e.Use(func() (interface{}, error) {
+		select {
+		case name := <-started:
+			t.Errorf("candidate %q started before the control finished", name)
+		default:
+		}
+		close(controlDone)
+		return 1, nil
+	})
+
+	candidate := func(name string) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			started <- name
+			// both candidates have to run at once to get past this
+			<-release
+			return 1, nil
+		}
+	}
+	e.Behavior("a", candidate("a"))
+	e.Behavior("b", candidate("b"))
+
+	go func() {
+		<-started
+		<-started
+		close(release)
+	}()
+
+	v, err := e.Run()
+	if v != 1 || err != nil {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+
+	select {
+	case <-controlDone:
+	default:
+		t.Errorf("expected control to run")
+	}
+}
+
+func TestExperimentMaxSlowdown(t *testing.T) {
+	e := New("slowdown")
+	e.MaxSlowdown(2)
+	e.Use(func() (interface{}, error) {
+		time.Sleep(5 * time.Millisecond)
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		time.Sleep(50 * time.Millisecond)
+		return 1, nil
+	})
+	e.Behavior("fast", func() (interface{}, error) {
+		return 1, nil
+	})
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	r := Run(e, "control")
+	for _, c := range r.Candidates {
+		switch c.Name {
+		case "candidate":
+			if c.Slowdown <= 2 {
+				t.Errorf("Unexpected slowdown for %q: %v", c.Name, c.Slowdown)
+			}
+		case "fast":
+			if c.Slowdown <= 0 || c.Slowdown >= 1 {
+				t.Errorf("Unexpected slowdown for %q: %v", c.Name, c.Slowdown)
+			}
+		}
+	}
+
+	if len(reported) != 1 || reported[0].Operation != "performance" || !strings.Contains(reported[0].Error(), `"candidate"`) {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+}
+
+func TestExperimentRunCandidateTimes(t *testing.T) {
+	calls := 0
+
+	e := New("flaky")
+	e.RunCandidateTimes(3)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		calls++
+		return calls, nil
+	})
+	e.Behavior("stable", func() (interface{}, error) {
+		return 2, nil
+	})
+
+	r := Run(e, "control")
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"stable"})
+
+	for _, c := range r.Candidates {
+		if c.Unstable != (c.Name == "candidate") {
+			t.Errorf("Unexpected stability for %q: %v", c.Name, c.Unstable)
+		}
+	}
+
+	if calls != 2 {
+		t.Errorf("expected repeats to stop once the candidate is unstable, got %d calls", calls)
+	}
+}
+
+func TestExperimentRunControlTimes(t *testing.T) {
+	for _, concurrent := range []bool{false, true} {
+		calls := 0
+
+		e := New("flaky-control")
+		e.RunControlTimes(3)
+		if concurrent {
+			e.EnableConcurrency(time.Second)
+		}
+		e.Use(func() (interface{}, error) {
+			calls++
+			return calls, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return 2, nil
+		})
+
+		var published Result
+		e.Publish(func(r Result) error {
+			published = r
+			return nil
+		})
+
+		v, err := e.Run()
+		if v != 1 || err != nil {
+			t.Errorf("Expected the first control value: %v, %v", v, err)
+		}
+
+		if !published.Control.Unstable || !published.IsMismatched() {
+			t.Errorf("Expected an unstable control with concurrency %v: %+v", concurrent, published.Control)
+		}
+
+		if calls != 2 {
+			t.Errorf("expected repeats to stop once the control is unstable, got %d calls", calls)
+		}
+	}
+}
+
+func TestExperimentAddContext(t *testing.T) {
+	e := New("add-context")
+	e.AddContext("service", "widgets")
+	e.AddContext("version", 3)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	var published Result
+	e.Publish(func(r Result) error {
+		published = r
+		return nil
+	})
+	e.Run()
+
+	p := published.Payload()
+	if p.Context["service"] != "widgets" || p.Context["version"] != "3" {
+		t.Errorf("Unexpected context: %v", p.Context)
+	}
+}
+
+func TestExperimentAddContextAfterRun(t *testing.T) {
+	e := New("add-context-after-run")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+	e.Run()
+
+	e.AddContext("service", "widgets")
+	if _, ok := e.Context["service"]; ok {
+		t.Errorf("Expected the context not to change after a run: %v", e.Context)
+	}
+
+	if len(reported) != 1 || reported[0].Operation != "configure" {
+		t.Errorf("Unexpected errors: %v", reported)
+	}
+}
+
+func TestExperimentCandidateVersion(t *testing.T) {
+	e := New("candidate-version")
+	e.CandidateVersion("candidate", "abc123")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+
+	var published Result
+	e.Publish(func(r Result) error {
+		published = r
+		return nil
+	})
+	e.Run()
+
+	if published.Control.Version != "" {
+		t.Errorf("Unexpected control version: %q", published.Control.Version)
+	}
+
+	p := published.Payload()
+	if len(p.Candidates) != 1 || p.Candidates[0].Version != "abc123" {
+		t.Errorf("Unexpected candidates: %+v", p.Candidates)
+	}
+}
+
+func TestExperimentRunWithContextData(t *testing.T) {
+	e := New("context-data")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+
+	var published []Result
+	e.Publish(func(r Result) error {
+		published = append(published, r)
+		return nil
+	})
+
+	v, err := e.RunWithContextData(map[string]interface{}{"request_id": "abc"})
+	if v != 1 || err != nil {
+		t.Errorf("Unexpected control: %v, %v", v, err)
+	}
+	e.Run()
+
+	if len(published) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(published))
+	}
+
+	if id := published[0].Payload().Data["request_id"]; id != "abc" {
+		t.Errorf("Unexpected request_id: %v", id)
+	}
+
+	if published[1].Data != nil {
+		t.Errorf("Expected data only on its own run: %v", published[1].Data)
+	}
+}
+
+func TestExperimentTryWithTimeout(t *testing.T) {
+	e := New("try-with-timeout")
+	e.EnableConcurrency(10 * time.Millisecond)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.TryWithTimeout("slow-backend", time.Second, func(ctx context.Context) (interface{}, error) {
+		select {
+		case <-time.After(50 * time.Millisecond):
+			return 1, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	})
+	e.TryWithTimeout("stuck", 10*time.Millisecond, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		return 1, nil
+	})
+
+	var aborted []string
+	e.Abort(func(name string) {
+		aborted = append(aborted, name)
+	})
+
+	r := Run(e, "control")
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"stuck"})
+	assertObservationNames(t, "timed out", r.TimedOut, []string{"stuck"})
+
+	if len(aborted) != 1 || aborted[0] != "stuck" {
+		t.Errorf("Unexpected aborted candidates: %v", aborted)
+	}
+}
+
+func TestExperimentTryWithTimeoutSequential(t *testing.T) {
+	e := New("try-with-timeout-sequential")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.TryWithTimeout("candidate", 10*time.Millisecond, func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	r := Run(e, "control")
+	if err := r.Candidates[0].Err; err != context.DeadlineExceeded {
+		t.Errorf("Unexpected candidate error: %v", err)
+	}
+}
+
+func TestExperimentConduct(t *testing.T) {
+	e := New("conduct")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Publish(func(r Result) error {
+		return nil
+	})
+
+	v, r, err := e.Conduct(context.Background())
+	if v != 1 || err != nil {
+		t.Errorf("Unexpected control: %v, %v", v, err)
+	}
+
+	if r.Control.Value != 1 || !r.IsMismatched() {
+		t.Errorf("Unexpected result: %+v", r)
+	}
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate"})
+
+	e.Disable()
+	t.Cleanup(e.Enable)
+	_, r, _ = e.Conduct(context.Background())
+	if r.SkipReason != SkipDisabled || len(r.Candidates) != 0 {
+		t.Errorf("Unexpected disabled result: %+v", r)
+	}
+}
+
+func TestExperimentConductAsync(t *testing.T) {
+	for _, async := range []func(*Experiment){(*Experiment).EnableAsync, (*Experiment).EnableAsyncComparison} {
+		e := New("conduct-async")
+		async(e)
+		e.Use(func() (interface{}, error) {
+			return 1, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return 2, nil
+		})
+		e.Clean(func(v interface{}) (interface{}, error) {
+			return v, nil
+		})
+		e.Publish(func(r Result) error {
+			return nil
+		})
+
+		for i := 0; i < 10; i++ {
+			_, r, _ := e.Conduct(context.Background())
+			if v, err := r.Control.CleanedValue(); v != 1 || err != nil {
+				t.Errorf("Unexpected cleaned control: %v, %v", v, err)
+			}
+			_ = r.Control.Version
+		}
+
+		if err := Shutdown(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExperimentConductControlOnly(t *testing.T) {
+	e := New("conduct-control")
+	e.Use(func() (interface{}, error) {
+		return nil, errors.New("control")
+	})
+
+	v, r, err := e.Conduct(context.Background())
+	if v != nil || err == nil || err.Error() != "control" {
+		t.Errorf("Unexpected control: %v, %v", v, err)
+	}
+
+	if r.Control == nil || r.Control.Err != err || len(r.Observations) != 1 {
+		t.Errorf("Expected a result with only the control: %+v", r)
+	}
+}
+
+func TestExperimentCandidateOrder(t *testing.T) {
+	for _, concurrent := range []bool{false, true} {
+		e := New("candidate-order")
+		if concurrent {
+			e.EnableConcurrency(0)
+		}
+		e.Use(func() (interface{}, error) {
+			return 1, nil
+		})
+		for i, name := range []string{"c", "a", "d", "b"} {
+			delay := time.Duration(4-i) * time.Millisecond
+			e.Behavior(name, func() (interface{}, error) {
+				time.Sleep(delay)
+				return 1, nil
+			})
+		}
+
+		r := Run(e, "control")
+		names := make([]string, len(r.Candidates))
+		for i, o := range r.Candidates {
+			names[i] = o.Name
+		}
+
+		if actual := strings.Join(names, " "); actual != "a b c d" {
+			t.Errorf("Unexpected candidate order with concurrency %t: %s", concurrent, actual)
+		}
+	}
+}
+
+func TestExperimentSkippedRunAllocations(t *testing.T) {
+	e := New("skipped-allocations")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		t.Errorf("Expected the candidate to be skipped")
+		return 1, nil
+	})
+	e.Publish(func(r Result) error {
+		t.Errorf("Unexpected published result: %v", r)
+		return nil
+	})
+	e.RunPercent(0)
+
+	if allocs := testing.AllocsPerRun(100, func() { e.Run() }); allocs != 0 {
+		t.Errorf("Expected no allocations for skipped runs, got %v", allocs)
+	}
+}
+
+func TestResultErrorBehavior(t *testing.T) {
+	errCompare := errors.New("compare")
+	e := New("result-error-behavior")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Compare(func(control, candidate interface{}) (bool, error) {
+		return false, fmt.Errorf("wrapped: %w", errCompare)
+	})
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+	e.Run()
+
+	if len(reported) != 1 {
+		t.Fatalf("Expected 1 error, got %v", reported)
+	}
+
+	if err := reported[0]; err.Operation != "compare" || err.Behavior != "candidate" || !errors.Is(err, errCompare) {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+
+	if p := reported[0].Payload(); p.Behavior != "candidate" {
+		t.Errorf("Unexpected payload: %+v", p)
+	}
+}
+
+func TestExperimentMultipleErrorReporters(t *testing.T) {
+	var first, second []ResultError
+	e := New("multiple-reporters")
+	e.ReportErrors(
+		func(errs ...ResultError) {
+			first = append(first, errs...)
+			panic("broken reporter")
+		},
+		func(errs ...ResultError) {
+			second = append(second, errs...)
+		},
+	)
+
+	e.ReportError("publish", errors.New("boom"))
+
+	if len(first) != 1 || len(second) != 1 {
+		t.Errorf("Expected both reporters to get the error: %v, %v", first, second)
+	}
+}
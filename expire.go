@@ -0,0 +1,58 @@
+package scientist
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const SkipExpired = "expired"
+
+var ErrExpired = errors.New("[scientist] experiment expired")
+
+// expiredNoticeInterval is how often an expired experiment reports that it's
+// still running.
+var expiredNoticeInterval = time.Hour
+
+// ExpiresAt disables the experiment at t, so only the control runs. Expired
+// experiments keep reporting ErrExpired as an "expired" error, once an hour,
+// until they're removed from the code.
+func (e *Experiment) ExpiresAt(t time.Time) {
+	if !e.configurable("ExpiresAt") {
+		return
+	}
+
+	e.expiresAt = t
+
+	var nanos int64
+	if !t.IsZero() {
+		nanos = t.UnixNano()
+	}
+	atomic.StoreInt64(&e.state.expiresAt, nanos)
+}
+
+// expiredNow only reads the clock for experiments with an expiry date.
+func (e *Experiment) expiredNow() bool {
+	return !e.expiresAt.IsZero() && e.expired(time.Now())
+}
+
+func (e *Experiment) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// noticeExpired reports ErrExpired at most once per interval across every
+// experiment with the same name.
+func (e *Experiment) noticeExpired(now time.Time) {
+	last := atomic.LoadInt64(&e.state.expiredNotice)
+	if last > 0 && now.Sub(time.Unix(0, last)) < expiredNoticeInterval {
+		return
+	}
+
+	if !atomic.CompareAndSwapInt64(&e.state.expiredNotice, last, now.UnixNano()) {
+		return
+	}
+
+	err := fmt.Errorf("%w on %s and is still running", ErrExpired, e.expiresAt.Format(time.RFC3339))
+	e.report(e.resultErr("expired", err))
+}
@@ -0,0 +1,71 @@
+package scientist
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestExpiresAt(t *testing.T) {
+	name := uniqueName("expires-at")
+	var reported []ResultError
+	var published []Result
+	run := func(expires time.Time) {
+		e := New(name)
+		e.ExpiresAt(expires)
+		e.Use(func() (interface{}, error) {
+			return 1, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return 1, nil
+		})
+		e.Publish(func(r Result) error {
+			published = append(published, r)
+			return nil
+		})
+		e.ReportErrors(func(errs ...ResultError) {
+			reported = append(reported, errs...)
+		})
+
+		if v, err := e.Run(); v != 1 || err != nil {
+			t.Errorf("Unexpected control result: %v, %v", v, err)
+		}
+	}
+
+	run(time.Now().Add(time.Hour))
+	if len(published) != 1 || published[0].SkipReason != "" || len(reported) != 0 {
+		t.Fatalf("Expected the experiment to run: %v, %v", published, reported)
+	}
+
+	run(time.Now().Add(-time.Hour))
+	run(time.Now().Add(-time.Hour))
+	if len(published) != 3 || published[1].SkipReason != SkipExpired || published[2].SkipReason != SkipExpired {
+		t.Errorf("Expected expired runs to skip candidates: %v", published)
+	}
+
+	if len(reported) != 1 {
+		t.Fatalf("Expected 1 expired notice, got %v", reported)
+	}
+
+	err := reported[0]
+	if err.Operation != "expired" || !errors.Is(err, ErrExpired) || err.Severity != SeverityWarning {
+		t.Errorf("Unexpected expired notice: %+v", err)
+	}
+
+	interval := expiredNoticeInterval
+	expiredNoticeInterval = 0
+	defer func() { expiredNoticeInterval = interval }()
+
+	run(time.Now().Add(-time.Hour))
+	if len(reported) != 2 {
+		t.Errorf("Expected another expired notice, got %v", reported)
+	}
+}
+
+func TestExpiredIsDisabled(t *testing.T) {
+	e := New("expired-is-disabled")
+	e.ExpiresAt(time.Now().Add(-time.Minute))
+	if !e.Disabled() || e.Stats().Enabled {
+		t.Errorf("Expected an expired experiment to be disabled")
+	}
+}
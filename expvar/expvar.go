@@ -0,0 +1,51 @@
+// Package expvar exports the counters of every registered experiment as the
+// "scientist" expvar variable, so they show up in /debug/vars. Import it for
+// its side effect:
+//
+//	import _ "scientist/expvar"
+package expvar
+
+import (
+	"expvar"
+	"scientist"
+	"time"
+)
+
+type Counters struct {
+	Enabled      bool       `json:"enabled"`
+	Runs         int64      `json:"runs"`
+	Matched      int64      `json:"matched"`
+	Mismatched   int64      `json:"mismatched"`
+	Ignored      int64      `json:"ignored"`
+	Skipped      int64      `json:"skipped"`
+	LastMismatch *time.Time `json:"last_mismatch,omitempty"`
+}
+
+func init() {
+	expvar.Publish("scientist", expvar.Func(Snapshot))
+}
+
+// Snapshot returns the counters of every registered experiment, keyed by
+// experiment name.
+func Snapshot() interface{} {
+	stats := scientist.AllStats()
+	counters := make(map[string]Counters, len(stats))
+	for _, s := range stats {
+		c := Counters{
+			Enabled:    s.Enabled,
+			Runs:       s.Runs,
+			Matched:    s.Matched,
+			Mismatched: s.Mismatched,
+			Ignored:    s.Ignored,
+			Skipped:    s.Skipped,
+		}
+
+		if !s.LastMismatch.IsZero() {
+			last := s.LastMismatch
+			c.LastMismatch = &last
+		}
+
+		counters[s.Name] = c
+	}
+	return counters
+}
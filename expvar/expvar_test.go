@@ -0,0 +1,45 @@
+package expvar
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"scientist"
+	"testing"
+)
+
+// snapshots numbers each run's experiment, since the counters registered by
+// name outlive a run with go test -count.
+var snapshots int
+
+func TestSnapshot(t *testing.T) {
+	snapshots++
+	name := fmt.Sprintf("expvar-%d", snapshots)
+	e := scientist.New(name)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Run()
+
+	v := expvar.Get("scientist")
+	if v == nil {
+		t.Fatalf("scientist var not published")
+	}
+
+	var counters map[string]Counters
+	if err := json.Unmarshal([]byte(v.String()), &counters); err != nil {
+		t.Fatal(err)
+	}
+
+	c, ok := counters[name]
+	if !ok {
+		t.Fatalf("experiment not exported: %v", counters)
+	}
+
+	if !c.Enabled || c.Runs != 1 || c.Mismatched != 1 || c.Matched != 0 || c.LastMismatch == nil {
+		t.Errorf("Unexpected counters: %+v", c)
+	}
+}
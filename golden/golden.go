@@ -0,0 +1,193 @@
+// Package golden snapshots control values in golden files, keyed by
+// experiment and input, to catch unintended changes to the control while an
+// experiment is running. Snapshots are stored as indented JSON:
+//
+//	<dir>/<experiment>/<input hash>.json
+package golden
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"scientist"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type Snapshots struct {
+	Dir string
+
+	// Update overwrites golden files with the current control values instead
+	// of comparing them.
+	Update bool
+}
+
+func New(dir string) *Snapshots {
+	return &Snapshots{Dir: dir}
+}
+
+// ChangedError is returned when a control value no longer matches its golden
+// file.
+type ChangedError struct {
+	Experiment string
+	Path       string
+	Diff       string
+}
+
+func (e ChangedError) Error() string {
+	return fmt.Sprintf("[scientist] %q control changed from %s:\n%s", e.Experiment, e.Path, e.Diff)
+}
+
+type snapshot struct {
+	Input interface{} `json:"input"`
+	Value interface{} `json:"value"`
+	Error string      `json:"error,omitempty"`
+}
+
+// Check returns an Experiment.AfterRun callback that compares the control's
+// cleaned value and error with the golden file for the input. A missing golden
+// file is written instead. Changes are returned as a ChangedError, and
+// reported with an "after_run" operation.
+//
+//	e.AfterRun(snapshots.Check(user))
+func (s *Snapshots) Check(input interface{}) func(scientist.Result) error {
+	in, inErr := normalize(input)
+	return func(r scientist.Result) error {
+		if inErr != nil {
+			return inErr
+		}
+		if r.Control == nil {
+			return nil
+		}
+
+		value, err := r.Control.CleanedValue()
+		if err != nil {
+			return err
+		}
+
+		current := snapshot{Input: in}
+		if current.Value, err = normalize(value); err != nil {
+			return err
+		}
+		if r.Control.Err != nil {
+			current.Error = r.Control.Err.Error()
+		}
+
+		path, err := s.Path(r.Experiment.Name, input)
+		if err != nil {
+			return err
+		}
+
+		if !s.Update {
+			golden, err := read(path)
+			if err == nil {
+				return compare(r.Experiment.Name, path, golden, current)
+			}
+			if !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		return write(path, current)
+	}
+}
+
+// Path returns the golden file for an experiment and input.
+func (s *Snapshots) Path(experiment string, input interface{}) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return filepath.Join(s.Dir, fileName(experiment), hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+func compare(experiment, path string, golden, current snapshot) error {
+	if reflect.DeepEqual(golden.Value, current.Value) && golden.Error == current.Error {
+		return nil
+	}
+
+	return ChangedError{
+		Experiment: experiment,
+		Path:       path,
+		Diff:       cmp.Diff(golden, current),
+	}
+}
+
+func read(path string) (snapshot, error) {
+	var s snapshot
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+
+	err = json.Unmarshal(data, &s)
+	return s, err
+}
+
+// write replaces the golden file atomically, since concurrent runs may check
+// the same input.
+func write(path string, s snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".golden-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// normalize round trips a value through JSON, so it compares equal to the
+// value read from a golden file.
+func normalize(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized interface{}
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+// fileName returns the directory name for an experiment's golden files. Names
+// that are only dots, or empty, get a prefix so they can't point at Dir or its
+// parent.
+func fileName(experiment string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			return r
+		default:
+			return '_'
+		}
+	}, experiment)
+
+	if strings.Trim(name, ".") == "" {
+		return "_" + name
+	}
+	return name
+}
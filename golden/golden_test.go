@@ -0,0 +1,123 @@
+package golden
+
+import (
+	"errors"
+	"os"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+type widget struct {
+	ID    int
+	Owner string
+}
+
+func TestCheck(t *testing.T) {
+	s := New(t.TempDir())
+	input := widget{1, "alice"}
+
+	errs := run(s, input, "alice")
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors writing golden file: %v", errs)
+	}
+
+	path, err := s.Path("golden widgets", input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"value": "alice"`) {
+		t.Errorf("Unexpected golden file:\n%s", data)
+	}
+
+	if errs := run(s, input, "alice"); len(errs) != 0 {
+		t.Errorf("Unexpected errors for unchanged control: %v", errs)
+	}
+
+	errs = run(s, input, "bob")
+	if len(errs) != 1 || errs[0].Operation != "after_run" {
+		t.Fatalf("Expected changed control error, got %v", errs)
+	}
+
+	var changed ChangedError
+	if !errors.As(errs[0].Err, &changed) {
+		t.Fatalf("Unexpected error type: %T", errs[0].Err)
+	}
+	if changed.Path != path || !strings.Contains(changed.Diff, `"bob"`) {
+		t.Errorf("Unexpected changed error: %+v", changed)
+	}
+
+	// Other inputs have their own golden file.
+	if errs := run(s, widget{2, "bob"}, "bob"); len(errs) != 0 {
+		t.Errorf("Unexpected errors for new input: %v", errs)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	s := New(t.TempDir())
+	input := widget{1, "alice"}
+	run(s, input, "alice")
+
+	s.Update = true
+	if errs := run(s, input, "bob"); len(errs) != 0 {
+		t.Fatalf("Unexpected errors updating golden file: %v", errs)
+	}
+
+	s.Update = false
+	if errs := run(s, input, "bob"); len(errs) != 0 {
+		t.Errorf("Unexpected errors after update: %v", errs)
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	s := New(t.TempDir())
+	input := widget{1, "alice"}
+
+	if errs := run(s, input, ""); len(errs) != 0 {
+		t.Fatalf("Unexpected errors writing golden file: %v", errs)
+	}
+	if errs := run(s, input, "alice"); len(errs) != 1 {
+		t.Errorf("Expected changed control error, got %v", errs)
+	}
+}
+
+func run(s *Snapshots, input widget, owner string) []scientist.ResultError {
+	var errs []scientist.ResultError
+	e := scientist.New("golden widgets")
+	e.Use(func() (interface{}, error) {
+		if owner == "" {
+			return nil, errors.New("no owner")
+		}
+		return owner, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return owner, nil
+	})
+	e.AfterRun(s.Check(input))
+	e.ReportErrors(func(r ...scientist.ResultError) {
+		errs = append(errs, r...)
+	})
+	e.Run()
+	return errs
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]string{
+		"widget-permissions": "widget-permissions",
+		"widgets/v2":         "widgets_v2",
+		"":                   "_",
+		".":                  "_.",
+		"..":                 "_..",
+		"v1.2":               "v1.2",
+	}
+
+	for experiment, expected := range tests {
+		if actual := fileName(experiment); actual != expected {
+			t.Errorf("Unexpected file name for %q: %q", experiment, actual)
+		}
+	}
+}
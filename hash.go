@@ -0,0 +1,75 @@
+package scientist
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Hash is a fingerprint of an observed value, like "sha256:9f86d0...".
+type Hash string
+
+// HashValues replaces each observed value with its Hash as soon as the
+// behavior returns, so experiments over very large values only keep and
+// compare hashes. Run still returns the control's real value. Compare, Clean,
+// Ignore, and Publish callbacks get the hashes, and mismatch diffs show the
+// two hashes instead of what changed.
+func (e *Experiment) HashValues() {
+	if !e.configurable("HashValues") {
+		return
+	}
+
+	e.hashValues = true
+}
+
+// HashValue returns the sha256 Hash of a value. Strings and byte slices are
+// hashed as they are. Other values are encoded as JSON first, which sorts map
+// keys, so equal maps have equal hashes.
+func HashValue(v interface{}) (Hash, error) {
+	var data []byte
+	switch t := v.(type) {
+	case string:
+		data = []byte(t)
+	case []byte:
+		data = t
+	case json.RawMessage:
+		data = t
+	default:
+		var err error
+		if data, err = json.Marshal(v); err != nil {
+			return "", err
+		}
+	}
+
+	sum := sha256.Sum256(data)
+	return Hash("sha256:" + hex.EncodeToString(sum[:])), nil
+}
+
+// hashObservation replaces the observation's value with its hash. The
+// control keeps its real value to return it. Values that can't be hashed are
+// kept, and reported with a "hash" operation.
+func hashObservation(e *Experiment, o *Observation, control bool) {
+	if !e.hashValues || o.Err != nil || o.SkipReason != "" {
+		return
+	}
+
+	h, err := HashValue(o.Value)
+	if err != nil {
+		e.report(e.behaviorErr("hash", o.Name, err))
+		return
+	}
+
+	if control {
+		o.returned = o.Value
+		o.substituted = true
+	}
+	o.Value = h
+}
+
+// returnValue is the value Run returns for the observation.
+func (o *Observation) returnValue() interface{} {
+	if o.substituted {
+		return o.returned
+	}
+	return o.Value
+}
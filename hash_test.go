@@ -0,0 +1,60 @@
+package scientist
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHashValues(t *testing.T) {
+	large := strings.Repeat("widget", 1000)
+
+	e := New("hash-values")
+	e.HashValues()
+	e.EnableConcurrency(0)
+	e.Use(func() (interface{}, error) {
+		return map[string]string{"a": large, "b": "b"}, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return map[string]string{"b": "b", "a": large}, nil
+	})
+	e.Behavior("changed", func() (interface{}, error) {
+		return map[string]string{"a": large, "b": "c"}, nil
+	})
+
+	var published Result
+	e.Publish(func(r Result) error {
+		published = r
+		return nil
+	})
+
+	v, err := e.Run()
+	if m, ok := v.(map[string]string); !ok || m["a"] != large || err != nil {
+		t.Fatalf("Expected the control's real value: %T, %v", v, err)
+	}
+
+	want, err := HashValue(map[string]string{"a": large, "b": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if published.Control.Value != want || published.Candidates[0].Value != want {
+		t.Errorf("Expected hashed values: %v, %v", published.Control.Value, published.Candidates[0].Value)
+	}
+
+	assertObservationNames(t, "mismatched", published.Mismatched, []string{"changed"})
+	if d := published.Mismatched[0].Diff; !strings.Contains(d, "sha256:") || strings.Contains(d, "widget") {
+		t.Errorf("Expected a diff of hashes: %s", d)
+	}
+}
+
+func TestHashValue(t *testing.T) {
+	a, _ := HashValue("abc")
+	b, _ := HashValue([]byte("abc"))
+	if a != b || a != "sha256:ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+		t.Errorf("Unexpected hashes: %q, %q", a, b)
+	}
+
+	if _, err := HashValue(func() {}); err == nil {
+		t.Errorf("Expected an error hashing a func")
+	}
+}
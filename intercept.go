@@ -0,0 +1,31 @@
+package scientist
+
+import "sync"
+
+var interceptors struct {
+	sync.RWMutex
+	fns []func(name string, r Result) error
+}
+
+// SetInterceptors replaces the hooks that see the Result of every run of every
+// experiment, including skipped runs. They run after AfterRun callbacks, aren't
+// sampled, and errors they return are reported as "intercept".
+func SetInterceptors(fns ...func(name string, r Result) error) {
+	interceptors.Lock()
+	interceptors.fns = fns
+	interceptors.Unlock()
+}
+
+func intercept(r Result) Result {
+	interceptors.RLock()
+	fns := interceptors.fns
+	interceptors.RUnlock()
+
+	for _, fn := range fns {
+		if err := fn(r.Experiment.Name, r); err != nil {
+			r.Errors = append(r.Errors, r.Experiment.resultErr("intercept", err))
+		}
+	}
+
+	return r
+}
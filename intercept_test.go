@@ -0,0 +1,64 @@
+package scientist
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSetInterceptors(t *testing.T) {
+	defer SetInterceptors()
+
+	var mu sync.Mutex
+	var results []Result
+	SetInterceptors(func(name string, r Result) error {
+		if name != "intercepted" {
+			return nil
+		}
+		mu.Lock()
+		results = append(results, r)
+		mu.Unlock()
+		return errors.New("intercepted")
+	})
+
+	var reported []ResultError
+	e := New("intercepted")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.SampleMatched(0)
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	e.Run()
+	e.Disable()
+	e.Run()
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 intercepted results, got %d", len(results))
+	}
+
+	if !results[0].IsMismatched() {
+		t.Errorf("Expected the first result to be mismatched")
+	}
+
+	if results[1].SkipReason != SkipDisabled {
+		t.Errorf("Unexpected skip reason: %q", results[1].SkipReason)
+	}
+
+	if len(reported) != 2 || reported[0].Operation != "intercept" {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+
+	SetInterceptors()
+	e.Enable()
+	e.Run()
+
+	if len(results) != 2 {
+		t.Errorf("Expected removed interceptors not to run")
+	}
+}
@@ -0,0 +1,186 @@
+package scientist
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ResultPayload struct {
+	Experiment string                 `json:"experiment"`
+	Context    map[string]string      `json:"context,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	Key        string                 `json:"key,omitempty"`
+	Bucket     float64                `json:"bucket,omitempty"`
+	Matched    bool                   `json:"matched"`
+	Mismatched bool                   `json:"mismatched"`
+	Ignored    bool                   `json:"ignored"`
+	SkipReason string                 `json:"skip_reason,omitempty"`
+	Variant    string                 `json:"variant,omitempty"`
+	Control    *ObservationPayload    `json:"control"`
+	Candidates []ObservationPayload   `json:"candidates"`
+	Skipped    []ObservationPayload   `json:"skipped,omitempty"`
+	Errors     []ResultErrorPayload   `json:"errors,omitempty"`
+
+	// MismatchedCandidates and IgnoredCandidates list candidate names.
+	MismatchedCandidates []string `json:"mismatched_candidates,omitempty"`
+	IgnoredCandidates    []string `json:"ignored_candidates,omitempty"`
+
+	// TimedOut lists candidates that didn't finish before the timeout.
+	TimedOut []string `json:"timed_out,omitempty"`
+}
+
+type ObservationPayload struct {
+	Name    string    `json:"name"`
+	Started time.Time `json:"started"`
+
+	// Runtime is in nanoseconds.
+	Runtime time.Duration `json:"runtime"`
+
+	// Value is the cleaned value. If cleaning fails, Value is nil and
+	// CleanError is set.
+	Value      interface{} `json:"value"`
+	CleanError string      `json:"clean_error,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	SkipReason string      `json:"skip_reason,omitempty"`
+
+	// IgnoreReason is set on ignored candidates with a reason from
+	// Experiment.IgnoreWithReason.
+	IgnoreReason string `json:"ignore_reason,omitempty"`
+
+	// Diff is set on mismatched candidates, in go-cmp's format.
+	Diff string `json:"diff,omitempty"`
+
+	// AllocBytes and Allocs are set with Experiment.MeasureAllocations.
+	AllocBytes uint64 `json:"alloc_bytes,omitempty"`
+	Allocs     uint64 `json:"allocs,omitempty"`
+
+	// Slowdown is a candidate's runtime divided by the control's.
+	Slowdown float64 `json:"slowdown,omitempty"`
+
+	// Unstable is set on candidates that returned different values when run
+	// more than once with Experiment.RunCandidateTimes.
+	Unstable bool `json:"unstable,omitempty"`
+
+	// TimedOut is set on candidates that didn't finish before the timeout.
+	// Their Runtime is how long they ran before they were abandoned.
+	TimedOut bool `json:"timed_out,omitempty"`
+
+	// Version is set with Experiment.CandidateVersion.
+	Version string `json:"version,omitempty"`
+
+	// Tags are added by the behavior with Tag.
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+type ResultErrorPayload struct {
+	Operation  string `json:"operation"`
+	Experiment string `json:"experiment"`
+	Behavior   string `json:"behavior,omitempty"`
+	Severity   string `json:"severity"`
+	Error      string `json:"error"`
+}
+
+func (r Result) Payload() ResultPayload {
+	p := ResultPayload{
+		Data:       r.Data,
+		Key:        r.Key,
+		Bucket:     r.Bucket,
+		Matched:    r.IsMatched(),
+		Mismatched: r.IsMismatched(),
+		Ignored:    r.IsIgnored(),
+		SkipReason: r.SkipReason,
+		Variant:    r.Variant,
+		Candidates: make([]ObservationPayload, len(r.Candidates)),
+	}
+
+	if r.Experiment != nil {
+		p.Experiment = r.Experiment.Name
+		p.Context = r.Experiment.Context
+	}
+
+	if r.Control != nil {
+		control := r.Control.Payload()
+		p.Control = &control
+	}
+
+	for i, o := range r.Candidates {
+		p.Candidates[i] = o.Payload()
+	}
+
+	for _, o := range r.Skipped {
+		p.Skipped = append(p.Skipped, o.Payload())
+	}
+
+	for _, err := range r.Errors {
+		p.Errors = append(p.Errors, err.Payload())
+	}
+
+	for _, o := range r.Mismatched {
+		p.MismatchedCandidates = append(p.MismatchedCandidates, o.Name)
+	}
+
+	for _, o := range r.Ignored {
+		p.IgnoredCandidates = append(p.IgnoredCandidates, o.Name)
+	}
+
+	for _, o := range r.TimedOut {
+		p.TimedOut = append(p.TimedOut, o.Name)
+	}
+
+	return p
+}
+
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Payload())
+}
+
+func (o Observation) Payload() ObservationPayload {
+	p := ObservationPayload{
+		Name:         o.Name,
+		Started:      o.Started,
+		Runtime:      o.Runtime,
+		SkipReason:   o.SkipReason,
+		IgnoreReason: o.IgnoreReason,
+		Diff:         o.Diff,
+		AllocBytes:   o.AllocBytes,
+		Allocs:       o.Allocs,
+		Slowdown:     o.Slowdown,
+		Unstable:     o.Unstable,
+		TimedOut:     o.TimedOut,
+		Version:      o.Version,
+		Tags:         o.Tags,
+	}
+
+	if o.Experiment != nil {
+		value, err := o.CleanedValue()
+		if err != nil {
+			p.CleanError = err.Error()
+		} else {
+			p.Value = value
+		}
+	} else {
+		p.Value = o.Value
+	}
+
+	if o.Err != nil {
+		p.Error = o.Err.Error()
+	}
+
+	return p
+}
+
+func (o Observation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Payload())
+}
+
+func (e ResultError) Payload() ResultErrorPayload {
+	p := ResultErrorPayload{Operation: e.Operation, Experiment: e.Experiment, Behavior: e.Behavior, Severity: e.Severity.String()}
+	if e.Err != nil {
+		p.Error = e.Err.Error()
+	}
+	return p
+}
+
+func (e ResultError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Payload())
+}
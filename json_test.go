@@ -0,0 +1,81 @@
+package scientist
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResultJSON(t *testing.T) {
+	e := New("json")
+	e.Context["user"] = "1"
+	e.Use(func() (interface{}, error) {
+		return "a", nil
+	})
+	e.Try(func() (interface{}, error) {
+		return nil, errors.New("candidate")
+	})
+	e.Clean(func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return strings.ToUpper(s), nil
+		}
+		return v, nil
+	})
+	e.Publish(func(r Result) error {
+		return errors.New("publish")
+	})
+	e.ReportErrors(func(errs ...ResultError) {})
+
+	r := Run(e, "control")
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p ResultPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Experiment != "json" || p.Context["user"] != "1" || !p.Mismatched || p.Matched {
+		t.Errorf("Unexpected payload: %s", data)
+	}
+
+	if p.Control == nil || p.Control.Name != "control" || p.Control.Value != "A" {
+		t.Errorf("Unexpected control: %+v", p.Control)
+	}
+
+	if len(p.Candidates) != 1 || p.Candidates[0].Error != "candidate" {
+		t.Errorf("Unexpected candidates: %+v", p.Candidates)
+	}
+
+	if len(p.MismatchedCandidates) != 1 || p.MismatchedCandidates[0] != "candidate" {
+		t.Errorf("Unexpected mismatched candidates: %v", p.MismatchedCandidates)
+	}
+
+	if len(p.Errors) != 1 || p.Errors[0].Operation != "publish" || p.Errors[0].Error != "publish" {
+		t.Errorf("Unexpected errors: %+v", p.Errors)
+	}
+}
+
+func TestObservationJSONCleanError(t *testing.T) {
+	e := New("json")
+	e.Clean(func(v interface{}) (interface{}, error) {
+		return nil, errors.New("clean")
+	})
+
+	data, err := json.Marshal(&Observation{Experiment: e, Name: "control", Value: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var p ObservationPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Value != nil || p.CleanError != "clean" {
+		t.Errorf("Unexpected payload: %s", data)
+	}
+}
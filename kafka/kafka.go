@@ -0,0 +1,61 @@
+// Package kafka publishes scientist results to a Kafka topic. Results are
+// batched and written asynchronously. Delivery errors are sent to the
+// experiment's ReportErrors callback with a "publish" operation.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"scientist"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type Publisher struct {
+	Writer *kafka.Writer
+}
+
+func New(topic string, brokers ...string) *Publisher {
+	p := &Publisher{}
+	p.Writer = &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Async:        true,
+		BatchTimeout: 100 * time.Millisecond,
+		Completion:   p.completion,
+	}
+	return p
+}
+
+// Publish queues the result to be written to Kafka. The message is keyed by
+// the experiment name.
+func (p *Publisher) Publish(r scientist.Result) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	return p.Writer.WriteMessages(context.Background(), kafka.Message{
+		Key:        []byte(r.Experiment.Name),
+		Value:      value,
+		WriterData: r.Experiment,
+	})
+}
+
+// Close flushes any pending results.
+func (p *Publisher) Close() error {
+	return p.Writer.Close()
+}
+
+func (p *Publisher) completion(messages []kafka.Message, err error) {
+	if err == nil {
+		return
+	}
+
+	for _, m := range messages {
+		if e, ok := m.WriterData.(*scientist.Experiment); ok {
+			e.ReportError("publish", err)
+		}
+	}
+}
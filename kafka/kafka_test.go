@@ -0,0 +1,33 @@
+package kafka
+
+import (
+	"errors"
+	"scientist"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestCompletionReportsErrors(t *testing.T) {
+	e := scientist.New("kafka")
+
+	var reported []scientist.ResultError
+	e.ReportErrors(func(errs ...scientist.ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	p := New("science", "localhost:9092")
+	p.completion([]kafka.Message{{WriterData: e}}, nil)
+	if len(reported) != 0 {
+		t.Errorf("Unexpected errors: %v", reported)
+	}
+
+	p.completion([]kafka.Message{{WriterData: e}}, errors.New("boom"))
+	if len(reported) != 1 {
+		t.Fatalf("Unexpected errors: %v", reported)
+	}
+
+	if err := reported[0]; err.Operation != "publish" || err.Experiment != "kafka" || err.Error() != "boom" {
+		t.Errorf("Unexpected error: %+v", err)
+	}
+}
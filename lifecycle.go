@@ -0,0 +1,68 @@
+package scientist
+
+import "context"
+
+// Behavior is a behavior that manages its own fixtures or connections. Setup
+// and Teardown are called around every Run, and aren't included in its
+// runtime.
+type Behavior interface {
+	Setup() error
+	Run() (interface{}, error)
+	Teardown() error
+}
+
+func (e *Experiment) UseBehavior(b Behavior) {
+	e.AddBehavior(controlBehavior, b)
+}
+
+func (e *Experiment) TryBehavior(b Behavior) {
+	e.AddBehavior(candidateBehavior, b)
+}
+
+// AddBehavior adds a named Behavior. If Setup fails, its error is the
+// behavior's error. Teardown errors are reported with a "teardown" operation.
+func (e *Experiment) AddBehavior(name string, b Behavior) {
+	if !e.behavior(name, b) {
+		return
+	}
+
+	if e.lifecycles == nil {
+		e.lifecycles = make(map[string]Behavior)
+	}
+
+	e.lifecycles[name] = b
+	e.behaviors[name] = func(ctx context.Context) (interface{}, error) {
+		return b.Run()
+	}
+}
+
+// setUp calls Setup on the named behavior, if it's a Behavior. The returned
+// func calls Teardown.
+func setUp(ctx context.Context, e *Experiment, name string, capturePanics bool) (func(), error) {
+	b := e.lifecycles[name]
+	if b == nil {
+		return func() {}, nil
+	}
+
+	var err error
+	if capturePanics {
+		_, err = callBehavior(ctx, func(context.Context) (interface{}, error) {
+			return nil, b.Setup()
+		})
+	} else {
+		err = b.Setup()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		_, err := callBehavior(ctx, func(context.Context) (interface{}, error) {
+			return nil, b.Teardown()
+		})
+		if err != nil {
+			e.report(e.behaviorErr("teardown", name, err))
+		}
+	}, nil
+}
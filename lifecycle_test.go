@@ -0,0 +1,145 @@
+package scientist
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fixtureBehavior struct {
+	value       int
+	setupErr    error
+	teardownErr error
+	calls       []string
+}
+
+func (b *fixtureBehavior) Setup() error {
+	b.calls = append(b.calls, "setup")
+	time.Sleep(20 * time.Millisecond)
+	return b.setupErr
+}
+
+func (b *fixtureBehavior) Run() (interface{}, error) {
+	b.calls = append(b.calls, "run")
+	return b.value, nil
+}
+
+func (b *fixtureBehavior) Teardown() error {
+	b.calls = append(b.calls, "teardown")
+	return b.teardownErr
+}
+
+func TestBehaviorLifecycle(t *testing.T) {
+	control := &fixtureBehavior{value: 1}
+	candidate := &fixtureBehavior{value: 1}
+
+	e := New("lifecycle")
+	e.UseBehavior(control)
+	e.TryBehavior(candidate)
+
+	var result Result
+	e.Publish(func(r Result) error {
+		result = r
+		return nil
+	})
+	e.ReportErrors(func(errs ...ResultError) {
+		t.Errorf("Unexpected errors: %v", errs)
+	})
+
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control: %v, %v", v, err)
+	}
+
+	if !result.IsMatched() {
+		t.Errorf("Unexpected mismatch:\n%s", result.Diff())
+	}
+
+	for name, b := range map[string]*fixtureBehavior{"control": control, "candidate": candidate} {
+		if len(b.calls) != 3 || b.calls[0] != "setup" || b.calls[1] != "run" || b.calls[2] != "teardown" {
+			t.Errorf("Unexpected %s calls: %v", name, b.calls)
+		}
+	}
+
+	// Setup isn't part of the runtime.
+	if result.Candidates[0].Runtime >= 20*time.Millisecond {
+		t.Errorf("Unexpected candidate runtime: %v", result.Candidates[0].Runtime)
+	}
+}
+
+func TestBehaviorLifecycleErrors(t *testing.T) {
+	setupErr := errors.New("no fixtures")
+	teardownErr := errors.New("still connected")
+	candidate := &fixtureBehavior{value: 1, setupErr: setupErr}
+	other := &fixtureBehavior{value: 1, teardownErr: teardownErr}
+
+	e := New("lifecycle-errors")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.TryBehavior(candidate)
+	e.AddBehavior("other", other)
+
+	var result Result
+	e.Publish(func(r Result) error {
+		result = r
+		return nil
+	})
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+	e.Run()
+
+	for _, o := range result.Candidates {
+		switch o.Name {
+		case "candidate":
+			if o.Err != setupErr {
+				t.Errorf("Expected setup error, got %v", o.Err)
+			}
+		case "other":
+			if o.Err != nil {
+				t.Errorf("Unexpected error: %v", o.Err)
+			}
+		}
+	}
+
+	if len(candidate.calls) != 1 {
+		t.Errorf("Expected only setup after it fails, got %v", candidate.calls)
+	}
+
+	if len(reported) != 1 || reported[0].Operation != "teardown" || reported[0].Err != teardownErr {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+}
+
+func TestBehaviorLifecycleWithoutCandidates(t *testing.T) {
+	control := &fixtureBehavior{value: 1}
+	e := New("lifecycle-control")
+	e.UseBehavior(control)
+
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control: %v, %v", v, err)
+	}
+	if len(control.calls) != 3 {
+		t.Errorf("Unexpected control calls: %v", control.calls)
+	}
+}
+
+type constBehavior int
+
+func (b constBehavior) Setup() error              { return nil }
+func (b constBehavior) Run() (interface{}, error) { return int(b), nil }
+func (b constBehavior) Teardown() error           { return nil }
+
+func TestBehaviorLifecycleValidation(t *testing.T) {
+	e := New("lifecycle-validation")
+	e.UseBehavior(constBehavior(1))
+	e.TryBehavior(nil)
+
+	err, ok := e.Validate().(ValidationError)
+	if !ok || len(err.Errs) != 1 || !strings.Contains(err.Errs[0].Error(), "nil callback") {
+		t.Errorf("Expected only the nil behavior to be invalid, got %v", err)
+	}
+}
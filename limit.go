@@ -0,0 +1,38 @@
+package scientist
+
+import "sync"
+
+const SkipShed = "shed"
+
+var candidateSlots struct {
+	sync.RWMutex
+	ch chan struct{}
+}
+
+func LimitCandidates(n int) {
+	candidateSlots.Lock()
+	defer candidateSlots.Unlock()
+
+	if n > 0 {
+		candidateSlots.ch = make(chan struct{}, n)
+	} else {
+		candidateSlots.ch = nil
+	}
+}
+
+func acquireCandidate() (release func(), ok bool) {
+	candidateSlots.RLock()
+	ch := candidateSlots.ch
+	candidateSlots.RUnlock()
+
+	if ch == nil {
+		return func() {}, true
+	}
+
+	select {
+	case ch <- struct{}{}:
+		return func() { <-ch }, true
+	default:
+		return nil, false
+	}
+}
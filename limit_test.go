@@ -0,0 +1,30 @@
+package scientist
+
+import "testing"
+
+func TestLimitCandidates(t *testing.T) {
+	LimitCandidates(1)
+	defer LimitCandidates(0)
+
+	release, ok := acquireCandidate()
+	if !ok {
+		t.Fatalf("expected a candidate slot")
+	}
+
+	e := basicExperiment()
+	r := Run(e, "control")
+	assertObservationNames(t, "candidate", r.Candidates, []string{})
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{})
+	assertObservationNames(t, "skipped", r.Skipped, []string{"candidate", "correct", "three"})
+	for _, o := range r.Skipped {
+		if o.SkipReason != SkipShed {
+			t.Errorf("Unexpected skip reason for %q: %q", o.Name, o.SkipReason)
+		}
+	}
+
+	release()
+
+	r = Run(e, "control")
+	assertObservationNames(t, "candidate", r.Candidates, []string{"candidate", "correct", "three"})
+	assertObservationNames(t, "skipped", r.Skipped, []string{})
+}
@@ -0,0 +1,122 @@
+// Package otel publishes scientist results as OpenTelemetry spans. Each
+// experiment run gets a span, with a child span for every observation. Use
+// Experiment.RunContext to link the spans to the caller's trace.
+package otel
+
+import (
+	"context"
+	"scientist"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "scientist"
+
+// Publisher returns a Publish callback that records spans with the given
+// tracer. A nil tracer uses the global tracer provider.
+func Publisher(tracer trace.Tracer) func(scientist.Result) error {
+	return func(r scientist.Result) error {
+		Trace(tracer, r)
+		return nil
+	}
+}
+
+// Trace records spans for a result after the fact, using the observation
+// start times and runtimes.
+func Trace(tracer trace.Tracer, r scientist.Result) {
+	if tracer == nil {
+		tracer = otel.Tracer(instrumentationName)
+	}
+
+	start, end := bounds(r)
+	ctx, span := tracer.Start(r.Context(), "scientist."+r.Experiment.Name,
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.String("scientist.experiment", r.Experiment.Name),
+			attribute.Bool("scientist.matched", r.IsMatched()),
+			attribute.Bool("scientist.mismatched", r.IsMismatched()),
+			attribute.Bool("scientist.ignored", r.IsIgnored()),
+		),
+	)
+
+	if r.SkipReason != "" {
+		span.SetAttributes(attribute.String("scientist.skip_reason", r.SkipReason))
+	}
+
+	for _, o := range r.Observations {
+		traceObservation(ctx, tracer, r, o)
+	}
+
+	for _, o := range r.Skipped {
+		traceObservation(ctx, tracer, r, o)
+	}
+
+	if r.IsMismatched() {
+		span.SetStatus(codes.Error, "observations mismatched")
+	}
+
+	for _, err := range r.Errors {
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String("scientist.operation", err.Operation),
+		))
+	}
+
+	span.End(trace.WithTimestamp(end))
+}
+
+func traceObservation(ctx context.Context, tracer trace.Tracer, r scientist.Result, o *scientist.Observation) {
+	_, span := tracer.Start(ctx, "scientist."+r.Experiment.Name+"."+o.Name,
+		trace.WithTimestamp(o.Started),
+		trace.WithAttributes(
+			attribute.String("scientist.experiment", r.Experiment.Name),
+			attribute.String("scientist.behavior", o.Name),
+			attribute.Bool("scientist.control", o == r.Control),
+			attribute.Int64("scientist.runtime_ns", int64(o.Runtime)),
+			attribute.Bool("scientist.mismatched", contains(r.Mismatched, o)),
+			attribute.Bool("scientist.ignored", contains(r.Ignored, o)),
+		),
+	)
+
+	if o.SkipReason != "" {
+		span.SetAttributes(attribute.String("scientist.skip_reason", o.SkipReason))
+	}
+
+	if o.Err != nil {
+		span.RecordError(o.Err)
+		span.SetStatus(codes.Error, o.Err.Error())
+	}
+
+	span.End(trace.WithTimestamp(o.Started.Add(o.Runtime)))
+}
+
+func bounds(r scientist.Result) (start, end time.Time) {
+	for _, o := range r.Observations {
+		if start.IsZero() || o.Started.Before(start) {
+			start = o.Started
+		}
+
+		if finished := o.Started.Add(o.Runtime); finished.After(end) {
+			end = finished
+		}
+	}
+
+	if start.IsZero() {
+		start = time.Now()
+		end = start
+	}
+
+	return start, end
+}
+
+func contains(obs []*scientist.Observation, o *scientist.Observation) bool {
+	for _, other := range obs {
+		if other == o {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"scientist"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestPublisher(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := provider.Tracer("test")
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+
+	e := scientist.New("traced")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, errors.New("candidate")
+	})
+	e.Publish(Publisher(tracer))
+
+	v, err := e.RunContext(ctx)
+	if v != 1 || err != nil {
+		t.Fatalf("Unexpected control result: %v, %v", v, err)
+	}
+	parent.End()
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+
+	run, ok := spans["scientist.traced"]
+	if !ok {
+		t.Fatalf("no experiment span: %v", spans)
+	}
+
+	if run.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("experiment span not linked to caller span")
+	}
+
+	for _, name := range []string{"control", "candidate"} {
+		span, ok := spans["scientist.traced."+name]
+		if !ok {
+			t.Errorf("no %s span", name)
+			continue
+		}
+
+		if span.Parent().SpanID() != run.SpanContext().SpanID() {
+			t.Errorf("%s span not linked to experiment span", name)
+		}
+	}
+
+	if candidate := spans["scientist.traced.candidate"]; candidate != nil && len(candidate.Events()) == 0 {
+		t.Errorf("expected candidate error to be recorded")
+	}
+}
@@ -0,0 +1,43 @@
+package scientist
+
+import "context"
+
+type argsKey struct{}
+
+func (e *Experiment) UseWith(fn func(args ...interface{}) (interface{}, error)) {
+	e.BehaviorWith(controlBehavior, fn)
+}
+
+func (e *Experiment) TryWith(fn func(args ...interface{}) (interface{}, error)) {
+	e.BehaviorWith(candidateBehavior, fn)
+}
+
+// BehaviorWith adds a behavior that's called with the arguments given to
+// RunWith.
+func (e *Experiment) BehaviorWith(name string, fn func(args ...interface{}) (interface{}, error)) {
+	if !e.behavior(name, fn) {
+		return
+	}
+
+	e.behaviors[name] = func(ctx context.Context) (interface{}, error) {
+		return fn(Args(ctx)...)
+	}
+}
+
+// RunWith runs the experiment with arguments for each behavior. A sealed
+// experiment can be run again with different arguments, even from several
+// goroutines at once.
+func (e *Experiment) RunWith(args ...interface{}) (interface{}, error) {
+	return e.RunContextWith(context.Background(), args...)
+}
+
+func (e *Experiment) RunContextWith(ctx context.Context, args ...interface{}) (interface{}, error) {
+	return e.RunContext(context.WithValue(ctx, argsKey{}, args))
+}
+
+// Args returns the arguments given to RunWith, for behaviors that take a
+// context.
+func Args(ctx context.Context) []interface{} {
+	args, _ := ctx.Value(argsKey{}).([]interface{})
+	return args
+}
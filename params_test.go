@@ -0,0 +1,56 @@
+package scientist
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestRunWith(t *testing.T) {
+	e := New("params")
+	e.UseWith(func(args ...interface{}) (interface{}, error) {
+		return args[0].(int) * 2, nil
+	})
+	e.TryWith(func(args ...interface{}) (interface{}, error) {
+		n := args[0].(int)
+		if n == 3 {
+			return n * n, nil
+		}
+		return n + n, nil
+	})
+	e.BehaviorContext("context", func(ctx context.Context) (interface{}, error) {
+		return Args(ctx)[0].(int) * 2, nil
+	})
+
+	var mu sync.Mutex
+	mismatched := make(map[interface{}]bool)
+	e.Publish(func(r Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.IsMismatched() {
+			mismatched[r.Control.Value] = true
+		}
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			v, err := e.RunWith(n)
+			if v != n*2 || err != nil {
+				t.Errorf("Unexpected control for %d: %v, %v", n, v, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if len(mismatched) != 1 || !mismatched[6] {
+		t.Errorf("Unexpected mismatches: %v", mismatched)
+	}
+
+	if args := Args(context.Background()); args != nil {
+		t.Errorf("Unexpected args: %v", args)
+	}
+}
@@ -0,0 +1,41 @@
+package scientist
+
+import "math/rand"
+
+type promotion struct {
+	name    string
+	percent float64
+}
+
+// Promote returns the named candidate's value instead of the control's for a
+// percentage of runs, to roll a candidate out gradually. Promoted runs use the
+// candidate as their control, like RunBehavior, so the old control runs as a
+// candidate and mismatches are still published. RunWithKey promotes the same
+// keys every time. Disabled and expired experiments aren't promoted.
+func (e *Experiment) Promote(name string, percent float64) {
+	if !e.configurable("Promote") {
+		return
+	}
+
+	e.promotion = &promotion{name: name, percent: percent}
+}
+
+// promoted returns the behavior a run should use as its control.
+func (e *Experiment) promoted(name string, opts runOptions) string {
+	p := e.promotion
+	if p == nil || name != controlBehavior || p.percent <= 0 {
+		return name
+	}
+
+	if opts.keyed {
+		if bucket(e.Name, opts.key) < p.percent {
+			return p.name
+		}
+		return name
+	}
+
+	if p.percent >= 100 || rand.Float64()*100 < p.percent {
+		return p.name
+	}
+	return name
+}
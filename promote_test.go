@@ -0,0 +1,107 @@
+package scientist
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPromote(t *testing.T) {
+	newExperiment := func(percent float64, published *[]Result) *Experiment {
+		e := New("promote")
+		e.Promote("candidate", percent)
+		e.Use(func() (interface{}, error) {
+			return "control", nil
+		})
+		e.Try(func() (interface{}, error) {
+			return "candidate", nil
+		})
+		e.Publish(func(r Result) error {
+			*published = append(*published, r)
+			return nil
+		})
+		return e
+	}
+
+	var published []Result
+	v, err := newExperiment(100, &published).Run()
+	if v != "candidate" || err != nil {
+		t.Errorf("Unexpected promoted result: %v, %v", v, err)
+	}
+
+	if len(published) != 1 {
+		t.Fatalf("Expected 1 published result, got %d", len(published))
+	}
+
+	r := published[0]
+	if r.Control.Name != "candidate" || len(r.Candidates) != 1 || r.Candidates[0].Name != "control" || !r.IsMismatched() {
+		t.Errorf("Expected the control to run as a candidate: %+v", r)
+	}
+
+	published = nil
+	v, err = newExperiment(0, &published).Run()
+	if v != "control" || err != nil {
+		t.Errorf("Unexpected result: %v, %v", v, err)
+	}
+
+	if len(published) != 1 || published[0].Control.Name != "control" {
+		t.Errorf("Expected the control to run: %v", published)
+	}
+}
+
+func TestPromoteKeyed(t *testing.T) {
+	promoted := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint(i)
+		e := New("promote-keyed")
+		e.Promote("candidate", 25)
+		e.Use(func() (interface{}, error) {
+			return "control", nil
+		})
+		e.Try(func() (interface{}, error) {
+			return "candidate", nil
+		})
+
+		v, _ := e.RunWithKey(key)
+		if want := bucket(e.Name, key) < 25; (v == "candidate") != want {
+			t.Fatalf("Unexpected promotion for key %q: %v", key, v)
+		}
+		if v == "candidate" {
+			promoted++
+		}
+	}
+
+	if promoted < 150 || promoted > 350 {
+		t.Errorf("Expected about 250 promoted keys, got %d", promoted)
+	}
+}
+
+func TestPromoteDisabled(t *testing.T) {
+	e := New("promote-disabled")
+	e.Promote("candidate", 100)
+	e.Use(func() (interface{}, error) {
+		return "control", nil
+	})
+	e.Try(func() (interface{}, error) {
+		return "candidate", nil
+	})
+	e.Disable()
+	defer e.Enable()
+
+	if v, _ := e.Run(); v != "control" {
+		t.Errorf("Expected a disabled experiment not to promote: %v", v)
+	}
+}
+
+func TestPromoteMissingBehavior(t *testing.T) {
+	e := New("promote-missing")
+	e.Promote("missing", 50)
+	e.Use(func() (interface{}, error) {
+		return "control", nil
+	})
+
+	err := e.Validate()
+	if err == nil || !strings.Contains(err.Error(), `promoted behavior "missing" not found`) {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package scientist
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -131,3 +132,170 @@ func TestPublishWithErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestPublishSampleMatched(t *testing.T) {
+	for _, candidate := range []int{1, 2} {
+		e := New("sample")
+		e.SampleMatched(0)
+		e.Use(func() (interface{}, error) {
+			return 1, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return candidate, nil
+		})
+
+		published := false
+		e.Publish(func(r Result) error {
+			published = true
+			return nil
+		})
+
+		e.Run()
+
+		if expected := candidate != 1; published != expected {
+			t.Errorf("Expected published=%t for candidate %d", expected, candidate)
+		}
+	}
+}
+
+func TestPublishScrubbed(t *testing.T) {
+	e := New("scrub")
+	e.Use(func() (interface{}, error) {
+		return "secret", nil
+	})
+	e.Try(func() (interface{}, error) {
+		return "other secret", nil
+	})
+	e.Scrub(func(v interface{}) interface{} {
+		return "[redacted]"
+	})
+
+	published := false
+	e.Publish(func(r Result) error {
+		published = true
+
+		for _, o := range r.Observations {
+			if o.Value != "[redacted]" {
+				t.Errorf("%q value not scrubbed: %v", o.Name, o.Value)
+			}
+		}
+
+		if r.Mismatched[0] != r.Candidates[0] {
+			t.Errorf("scrubbed observations are not shared across the result")
+		}
+
+		return nil
+	})
+
+	v, err := e.Run()
+	if v != "secret" {
+		t.Errorf("Unexpected control value: %v", v)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	if !published {
+		t.Errorf("results never published")
+	}
+}
+
+func TestOnMismatch(t *testing.T) {
+	e := basicExperiment()
+
+	published := 0
+	e.Publish(func(r Result) error {
+		published++
+		return nil
+	})
+
+	mismatches := 0
+	e.OnMismatch(func(r Result) error {
+		mismatches++
+		assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate", "three"})
+		return fmt.Errorf("(mismatch) %s", r.Experiment.Name)
+	})
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	e.Run()
+
+	if published != 1 || mismatches != 1 {
+		t.Errorf("Unexpected callbacks: %d published, %d mismatched", published, mismatches)
+	}
+
+	if len(reported) != 1 || reported[0].Operation != "mismatch" {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+}
+
+func TestOnMismatchSkipsMatches(t *testing.T) {
+	e := New("match")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.OnMismatch(func(r Result) error {
+		t.Errorf("did not expect OnMismatch callback to run")
+		return nil
+	})
+
+	e.Run()
+}
+
+func TestPublishCleanedValues(t *testing.T) {
+	e := New("clean")
+	e.Use(func() (interface{}, error) {
+		return "control", nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Clean(func(v interface{}) (interface{}, error) {
+		if s, ok := v.(string); ok {
+			return strings.ToUpper(s), nil
+		}
+		return nil, fmt.Errorf("(clean) %v", v)
+	})
+
+	published := false
+	e.Publish(func(r Result) error {
+		published = true
+
+		if r.Control.Cleaned != "CONTROL" || r.Control.CleanErr != nil {
+			t.Errorf("Unexpected cleaned control: %v, %v", r.Control.Cleaned, r.Control.CleanErr)
+		}
+
+		candidate := r.Candidates[0]
+		if candidate.Cleaned != nil || candidate.CleanErr == nil {
+			t.Errorf("Unexpected cleaned candidate: %v, %v", candidate.Cleaned, candidate.CleanErr)
+		}
+
+		if len(r.Errors) != 1 || r.Errors[0].Operation != "clean" {
+			t.Errorf("Unexpected result errors: %v", r.Errors)
+		}
+
+		return nil
+	})
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	e.Run()
+
+	if !published {
+		t.Errorf("results never published")
+	}
+
+	if len(reported) != 1 || reported[0].Error() != "(clean) 2" {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+}
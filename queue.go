@@ -0,0 +1,146 @@
+package scientist
+
+import (
+	"errors"
+	"sync"
+)
+
+type QueuePolicy int
+
+const (
+	// Block waits for room in the queue.
+	Block QueuePolicy = iota
+
+	// DropNewest drops the result being published when the queue is full.
+	DropNewest
+
+	// DropOldest drops the oldest queued result to make room.
+	DropOldest
+)
+
+var (
+	ErrPublishQueueFull   = errors.New("[scientist] publish queue is full")
+	ErrPublishQueueClosed = errors.New("[scientist] publish queue is closed")
+)
+
+// PublishQueue publishes results on background workers, so slow publishers
+// don't add latency to experiment runs. Errors from the wrapped publisher
+// and dropped results are reported to each result's experiment.
+type PublishQueue struct {
+	publisher func(Result) error
+	policy    QueuePolicy
+	results   chan Result
+	workers   sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	pendingMu sync.Mutex
+	pending   int
+	drained   *sync.Cond
+}
+
+func NewPublishQueue(publisher func(Result) error, size, workers int, policy QueuePolicy) *PublishQueue {
+	if workers < 1 {
+		workers = 1
+	}
+
+	q := &PublishQueue{
+		publisher: publisher,
+		policy:    policy,
+		results:   make(chan Result, size),
+	}
+	q.drained = sync.NewCond(&q.pendingMu)
+
+	q.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+
+	trackQueue(q)
+	return q
+}
+
+func (q *PublishQueue) Publish(r Result) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrPublishQueueClosed
+	}
+
+	q.add(1)
+
+	switch q.policy {
+	case DropNewest:
+		select {
+		case q.results <- r:
+		default:
+			q.add(-1)
+			return ErrPublishQueueFull
+		}
+	case DropOldest:
+		for {
+			select {
+			case q.results <- r:
+				return nil
+			default:
+			}
+
+			select {
+			case old := <-q.results:
+				q.add(-1)
+				old.Experiment.ReportError("publish", ErrPublishQueueFull)
+			default:
+			}
+		}
+	default:
+		q.results <- r
+	}
+
+	return nil
+}
+
+// Flush waits for every queued result to be published.
+func (q *PublishQueue) Flush() {
+	q.pendingMu.Lock()
+	for q.pending > 0 {
+		q.drained.Wait()
+	}
+	q.pendingMu.Unlock()
+}
+
+// Close publishes any queued results and stops the workers. Results
+// published after Close return ErrPublishQueueClosed.
+func (q *PublishQueue) Close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.results)
+	q.mu.Unlock()
+
+	untrackQueue(q)
+	q.workers.Wait()
+}
+
+func (q *PublishQueue) work() {
+	defer q.workers.Done()
+	for r := range q.results {
+		if err := q.publisher(r); err != nil {
+			r.Experiment.ReportError("publish", err)
+		}
+		q.add(-1)
+	}
+}
+
+func (q *PublishQueue) add(delta int) {
+	q.pendingMu.Lock()
+	q.pending += delta
+	if q.pending == 0 {
+		q.drained.Broadcast()
+	}
+	q.pendingMu.Unlock()
+}
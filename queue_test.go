@@ -0,0 +1,104 @@
+package scientist
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPublishQueue(t *testing.T) {
+	var mu sync.Mutex
+	published := 0
+	q := NewPublishQueue(func(r Result) error {
+		mu.Lock()
+		published++
+		mu.Unlock()
+		return nil
+	}, 10, 2, Block)
+
+	e := basicExperiment()
+	e.Publish(q.Publish)
+	for i := 0; i < 25; i++ {
+		e.Run()
+	}
+
+	q.Flush()
+	mu.Lock()
+	if published != 25 {
+		t.Errorf("Expected 25 published results, got %d", published)
+	}
+	mu.Unlock()
+
+	q.Close()
+	if err := q.Publish(Result{Experiment: e}); err != ErrPublishQueueClosed {
+		t.Errorf("Unexpected error after close: %v", err)
+	}
+}
+
+func TestPublishQueueDropNewest(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	q := NewPublishQueue(func(r Result) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}, 1, 1, DropNewest)
+	defer q.Close()
+
+	e := New("queue")
+
+	// the worker picks up the first result, and the second fills the queue
+	if err := q.Publish(Result{Experiment: e}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if err := q.Publish(Result{Experiment: e}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := q.Publish(Result{Experiment: e}); err != ErrPublishQueueFull {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	close(release)
+	q.Flush()
+}
+
+func TestPublishQueueDropOldest(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	var published []string
+	q := NewPublishQueue(func(r Result) error {
+		published = append(published, r.Key)
+		started <- struct{}{}
+		<-release
+		return nil
+	}, 1, 1, DropOldest)
+	defer q.Close()
+
+	var reported []ResultError
+	e := New("queue")
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := q.Publish(Result{Experiment: e, Key: key}); err != nil {
+			t.Fatal(err)
+		}
+
+		if key == "a" {
+			<-started
+		}
+	}
+
+	close(release)
+	q.Flush()
+
+	if len(published) != 2 || published[0] != "a" || published[1] != "c" {
+		t.Errorf("Unexpected published results: %v", published)
+	}
+
+	if len(reported) != 1 || reported[0].Err != ErrPublishQueueFull {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+}
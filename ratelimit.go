@@ -0,0 +1,60 @@
+package scientist
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const SkipRateLimited = "rate_limited"
+
+type rateLimit struct {
+	perSecond float64
+	burst     float64
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func (e *Experiment) RunRate(perSecond float64, burst int) {
+	if !e.configurable("RunRate") {
+		return
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	e.rateLimit = &rateLimit{perSecond: perSecond, burst: float64(burst)}
+}
+
+// allowRun takes a token from the bucket shared by every experiment with the
+// same name.
+func (e *Experiment) allowRun() bool {
+	if e.rateLimit == nil {
+		return true
+	}
+	return e.state.bucket.take(e.rateLimit, time.Now())
+}
+
+func (b *tokenBucket) take(l *rateLimit, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.last.IsZero() {
+		b.tokens = l.burst
+	} else {
+		b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.perSecond)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
@@ -0,0 +1,70 @@
+package scientist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	l := &rateLimit{perSecond: 10, burst: 2}
+	b := &tokenBucket{}
+	now := time.Now()
+
+	if !b.take(l, now) || !b.take(l, now) {
+		t.Errorf("expected the burst to be allowed")
+	}
+
+	if b.take(l, now) {
+		t.Errorf("expected an empty bucket")
+	}
+
+	if !b.take(l, now.Add(100*time.Millisecond)) {
+		t.Errorf("expected a token after 100ms")
+	}
+
+	if b.take(l, now.Add(100*time.Millisecond)) {
+		t.Errorf("expected an empty bucket")
+	}
+
+	if !b.take(l, now.Add(time.Hour)) || !b.take(l, now.Add(time.Hour)) || b.take(l, now.Add(time.Hour)) {
+		t.Errorf("expected the bucket to hold at most the burst")
+	}
+}
+
+func TestRunRate(t *testing.T) {
+	runs := 0
+
+	e := New(uniqueName("rate"))
+	e.RunRate(0.001, 1)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		runs++
+		return 1, nil
+	})
+
+	var published []Result
+	e.Publish(func(r Result) error {
+		published = append(published, r)
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if v, err := e.Run(); v != 1 || err != nil {
+			t.Errorf("Unexpected control result: %v, %v", v, err)
+		}
+	}
+
+	if runs != 1 {
+		t.Errorf("Unexpected candidate runs: %d", runs)
+	}
+
+	if len(published) != 3 || published[0].SkipReason != "" || published[2].SkipReason != SkipRateLimited {
+		t.Errorf("Unexpected published results: %v", published)
+	}
+
+	if skipped := e.Stats().Skipped; skipped != 2 {
+		t.Errorf("Unexpected skipped runs: %d", skipped)
+	}
+}
@@ -0,0 +1,88 @@
+// Package redis publishes scientist results to Redis. Each result increments
+// a counter:
+//
+//	scientist:<experiment>:matched
+//	scientist:<experiment>:mismatched
+//	scientist:<experiment>:ignored
+//
+// RunVariant results aren't compared, so they increment a counter for the
+// behavior that ran instead:
+//
+//	scientist:<experiment>:variant:<behavior>
+//
+// Mismatched results are also pushed as JSON to a capped list for debugging
+// later:
+//
+//	scientist:<experiment>:mismatches
+package redis
+
+import (
+	"encoding/json"
+	"scientist"
+)
+
+// Conn runs a single Redis command. It matches the Do method of a redigo
+// connection, and is easy to adapt to other clients. It must be safe to call
+// from multiple goroutines if experiments are.
+type Conn interface {
+	Do(command string, args ...interface{}) (interface{}, error)
+}
+
+type Publisher struct {
+	// Prefix is prepended to every key. It defaults to "scientist".
+	Prefix string
+
+	// MaxMismatches is the length of the capped mismatches list. It defaults
+	// to 1000.
+	MaxMismatches int
+
+	conn Conn
+}
+
+func New(conn Conn) *Publisher {
+	return &Publisher{Prefix: "scientist", MaxMismatches: 1000, conn: conn}
+}
+
+func (p *Publisher) Publish(r scientist.Result) error {
+	switch {
+	case r.SkipReason != "":
+		_, err := p.conn.Do("INCR", p.key(r, r.SkipReason))
+		return err
+	case r.Variant != "":
+		_, err := p.conn.Do("INCR", p.key(r, "variant:"+r.Variant))
+		return err
+	case r.IsMismatched():
+		if _, err := p.conn.Do("INCR", p.key(r, "mismatched")); err != nil {
+			return err
+		}
+		return p.pushMismatch(r)
+	case r.IsIgnored():
+		_, err := p.conn.Do("INCR", p.key(r, "ignored"))
+		return err
+	default:
+		_, err := p.conn.Do("INCR", p.key(r, "matched"))
+		return err
+	}
+}
+
+func (p *Publisher) pushMismatch(r scientist.Result) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	key := p.key(r, "mismatches")
+	if _, err := p.conn.Do("LPUSH", key, data); err != nil {
+		return err
+	}
+
+	_, err = p.conn.Do("LTRIM", key, 0, p.MaxMismatches-1)
+	return err
+}
+
+func (p *Publisher) key(r scientist.Result, suffix string) string {
+	if len(p.Prefix) == 0 {
+		return r.Experiment.Name + ":" + suffix
+	}
+	return p.Prefix + ":" + r.Experiment.Name + ":" + suffix
+}
@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"fmt"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+type conn struct {
+	commands []string
+}
+
+func (c *conn) Do(command string, args ...interface{}) (interface{}, error) {
+	parts := []string{command}
+	for _, arg := range args {
+		if b, ok := arg.([]byte); ok {
+			arg = string(b)
+		}
+		parts = append(parts, fmt.Sprintf("%v", arg))
+	}
+	c.commands = append(c.commands, strings.Join(parts, " "))
+	return nil, nil
+}
+
+func TestPublishMatch(t *testing.T) {
+	c := &conn{}
+	e := scientist.New("redis")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Publish(New(c).Publish)
+	e.Run()
+
+	if len(c.commands) != 1 || c.commands[0] != "INCR scientist:redis:matched" {
+		t.Errorf("Unexpected commands: %q", c.commands)
+	}
+}
+
+func TestPublishVariant(t *testing.T) {
+	c := &conn{}
+	e := scientist.New("redis-variant")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Publish(New(c).Publish)
+
+	variant := e.Variant("key")
+	e.RunVariant("key")
+
+	if len(c.commands) != 1 || c.commands[0] != "INCR scientist:redis-variant:variant:"+variant {
+		t.Errorf("Unexpected commands: %q", c.commands)
+	}
+}
+
+func TestPublishDisabled(t *testing.T) {
+	c := &conn{}
+	e := scientist.New("redis-disabled")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Publish(New(c).Publish)
+	e.Disable()
+	defer e.Enable()
+	e.Run()
+
+	if len(c.commands) != 1 || c.commands[0] != "INCR scientist:redis-disabled:disabled" {
+		t.Errorf("Unexpected commands: %q", c.commands)
+	}
+}
+
+func TestPublishMismatch(t *testing.T) {
+	c := &conn{}
+	p := New(c)
+	p.MaxMismatches = 10
+
+	e := scientist.New("redis")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Publish(p.Publish)
+	e.Run()
+
+	if len(c.commands) != 3 {
+		t.Fatalf("Unexpected commands: %q", c.commands)
+	}
+
+	if c.commands[0] != "INCR scientist:redis:mismatched" {
+		t.Errorf("Unexpected counter: %q", c.commands[0])
+	}
+
+	if !strings.HasPrefix(c.commands[1], `LPUSH scientist:redis:mismatches {"experiment":"redis"`) {
+		t.Errorf("Unexpected push: %q", c.commands[1])
+	}
+
+	if c.commands[2] != "LTRIM scientist:redis:mismatches 0 9" {
+		t.Errorf("Unexpected trim: %q", c.commands[2])
+	}
+}
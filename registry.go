@@ -0,0 +1,160 @@
+package scientist
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Experiments are registered by name as they're created. Experiments are
+// usually created for every call, so the counters, kill switch, and settings
+// are shared by every experiment with the same name. Only that state is kept,
+// never the experiments themselves, so their behaviors can be collected.
+var registry sync.Map // name => *experimentState
+
+type experimentState struct {
+	// int64 fields come first so they're aligned for atomic access on 32-bit
+	// platforms.
+	runs          int64
+	matched       int64
+	mismatched    int64
+	ignored       int64
+	skipped       int64
+	lastMismatch  int64
+	expiredNotice int64
+	expiresAt     int64 // the latest ExpiresAt, in Unix nanoseconds
+	adaptive      adaptiveState
+	created       int32
+	disabled      int32
+	settings      atomic.Value // *Settings
+	breakers      sync.Map     // candidate name => *breaker
+	bucket        tokenBucket
+	dedup         dedupWindow
+}
+
+type Stats struct {
+	Name         string
+	Enabled      bool
+	Runs         int64
+	Matched      int64
+	Mismatched   int64
+	Ignored      int64
+	Skipped      int64
+	LastMismatch time.Time
+}
+
+func (s Stats) MatchRate() float64 {
+	return rate(s.Matched, s.Runs)
+}
+
+func (s Stats) MismatchRate() float64 {
+	return rate(s.Mismatched, s.Runs)
+}
+
+func rate(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// Lookup returns an experiment for the state shared by every experiment with
+// the name, to enable, disable, or get the stats of it. It has no behaviors,
+// so it can't be run.
+func Lookup(name string) (*Experiment, bool) {
+	s, ok := registry.Load(name)
+	if !ok || atomic.LoadInt32(&s.(*experimentState).created) == 0 {
+		return nil, false
+	}
+	return handle(name, s.(*experimentState)), true
+}
+
+func AllStats() []Stats {
+	var stats []Stats
+	registry.Range(func(name, s interface{}) bool {
+		if state := s.(*experimentState); atomic.LoadInt32(&state.created) == 1 {
+			stats = append(stats, handle(name.(string), state).Stats())
+		}
+		return true
+	})
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats
+}
+
+// Unregister forgets the counters, kill switch, and settings shared by
+// experiments with the name. Experiments that were already created keep
+// theirs, and the next one starts over.
+func Unregister(name string) {
+	registry.Delete(name)
+}
+
+// handle returns an experiment with only a name's shared state.
+func handle(name string, s *experimentState) *Experiment {
+	e := &Experiment{Name: name, state: s, validation: &validation{}}
+	if nanos := atomic.LoadInt64(&s.expiresAt); nanos != 0 {
+		e.expiresAt = time.Unix(0, nanos)
+	}
+	return e
+}
+
+func (e *Experiment) Stats() Stats {
+	s := Stats{
+		Name:       e.Name,
+		Enabled:    !e.Disabled(),
+		Runs:       atomic.LoadInt64(&e.state.runs),
+		Matched:    atomic.LoadInt64(&e.state.matched),
+		Mismatched: atomic.LoadInt64(&e.state.mismatched),
+		Ignored:    atomic.LoadInt64(&e.state.ignored),
+		Skipped:    atomic.LoadInt64(&e.state.skipped),
+	}
+
+	if nanos := atomic.LoadInt64(&e.state.lastMismatch); nanos > 0 {
+		s.LastMismatch = time.Unix(0, nanos)
+	}
+
+	return s
+}
+
+func register(e *Experiment) *experimentState {
+	s := registered(e.Name)
+	if atomic.LoadInt32(&s.created) == 0 {
+		atomic.StoreInt32(&s.created, 1)
+	}
+	return s
+}
+
+// registered returns the state for a name, even if no experiment has been
+// created with it yet. Names are usually registered already, so loading them
+// doesn't lock anything.
+func registered(name string) *experimentState {
+	if s, ok := registry.Load(name); ok {
+		return s.(*experimentState)
+	}
+
+	s, _ := registry.LoadOrStore(name, &experimentState{})
+	return s.(*experimentState)
+}
+
+func (s *experimentState) record(r Result) {
+	if r.SkipReason != "" {
+		atomic.AddInt64(&s.skipped, 1)
+		return
+	}
+
+	atomic.AddInt64(&s.runs, 1)
+	switch {
+	case r.Variant != "":
+		// a variant has nothing to compare against.
+	case r.IsMismatched():
+		atomic.AddInt64(&s.mismatched, 1)
+		atomic.StoreInt64(&s.lastMismatch, time.Now().UnixNano())
+	case r.IsIgnored():
+		atomic.AddInt64(&s.ignored, 1)
+	default:
+		atomic.AddInt64(&s.matched, 1)
+	}
+}
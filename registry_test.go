@@ -0,0 +1,71 @@
+package scientist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	name := uniqueName("lookup")
+	if _, ok := Lookup(name); ok {
+		t.Fatalf("Expected no experiment before one is created")
+	}
+
+	e := New(name)
+	e.ExpiresAt(time.Now().Add(time.Hour))
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Run()
+
+	found, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("Expected to find the experiment")
+	}
+
+	if s := found.Stats(); s.Name != name || !s.Enabled || s.Runs != 1 || s.Mismatched != 1 {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+
+	found.Disable()
+	if !e.Disabled() {
+		t.Errorf("Expected the kill switch to be shared by name")
+	}
+	found.Enable()
+
+	e2 := New(name)
+	e2.ExpiresAt(time.Now().Add(-time.Hour))
+	for _, s := range AllStats() {
+		if s.Name == name && s.Enabled {
+			t.Errorf("Expected the latest ExpiresAt to disable the experiment: %+v", s)
+		}
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	name := uniqueName("unregister")
+	e := New(name)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Run()
+	if s := e.Stats(); s.Runs != 1 {
+		t.Fatalf("Unexpected stats: %+v", s)
+	}
+	e.Disable()
+
+	Unregister(name)
+	if _, ok := Lookup(name); ok {
+		t.Errorf("Expected the experiment to be forgotten")
+	}
+
+	if s := New(name).Stats(); s.Runs != 0 || !s.Enabled {
+		t.Errorf("Expected a new experiment to start over: %+v", s)
+	}
+}
@@ -0,0 +1,175 @@
+// Package replay records the inputs and control outputs of experiments in
+// production, and replays them later to try candidates offline. Recordings
+// are JSON lines, one for each experiment run.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"scientist"
+	"sync"
+)
+
+// Record is a single recorded run.
+type Record struct {
+	Experiment string            `json:"experiment"`
+	Context    map[string]string `json:"context,omitempty"`
+	Input      json.RawMessage   `json:"input"`
+	Output     json.RawMessage   `json:"output"`
+	Error      string            `json:"error,omitempty"`
+}
+
+type Recorder struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// Create opens a recording file for appending, creating it if needed.
+func Create(path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{w: f, closer: f}, nil
+}
+
+// Record returns an Experiment.AfterRun callback that records the input with
+// the control's value and error. Values are recorded before they are cleaned.
+// Like other AfterRun callbacks, it's only called when the experiment runs
+// its candidates.
+//
+//	e.AfterRun(recorder.Record(user))
+func (r *Recorder) Record(input interface{}) func(scientist.Result) error {
+	in, inErr := json.Marshal(input)
+	return func(res scientist.Result) error {
+		if inErr != nil {
+			return inErr
+		}
+		if res.Control == nil {
+			return nil
+		}
+
+		rec := Record{
+			Experiment: res.Experiment.Name,
+			Context:    res.Experiment.Context,
+			Input:      in,
+		}
+
+		out, err := json.Marshal(res.Control.Value)
+		if err != nil {
+			return err
+		}
+		rec.Output = out
+
+		if res.Control.Err != nil {
+			rec.Error = res.Control.Err.Error()
+		}
+
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		_, err = r.w.Write(append(line, '\n'))
+		return err
+	}
+}
+
+// Close closes the file opened by Create.
+func (r *Recorder) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// Replay runs a new experiment for each recording of the named experiment.
+// The control returns the recorded output and error, and setup adds the
+// candidates to try with the recorded input, along with a publisher to catch
+// mismatches:
+//
+//	err := replay.Replay(f, "widget-permissions", func(e *scientist.TypedExperiment[bool], user User) {
+//	  e.Try(func() (bool, error) {
+//	    return newPermissionCheck(user)
+//	  })
+//	  e.Publish(store.Publish)
+//	})
+//
+// Records for other experiments are skipped. Replay stops at the first record
+// that can't be decoded.
+func Replay[In, Out any](r io.Reader, name string, setup func(e *scientist.TypedExperiment[Out], input In)) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return fmt.Errorf("[scientist] replay line %d: %w", line, err)
+		}
+		if rec.Experiment != name {
+			continue
+		}
+
+		if err := replay(rec, setup); err != nil {
+			return fmt.Errorf("[scientist] replay line %d: %w", line, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// ReplayFile replays the recordings in the file at path.
+func ReplayFile[In, Out any](path, name string, setup func(e *scientist.TypedExperiment[Out], input In)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return Replay(f, name, setup)
+}
+
+func replay[In, Out any](rec Record, setup func(e *scientist.TypedExperiment[Out], input In)) error {
+	var input In
+	if err := json.Unmarshal(rec.Input, &input); err != nil {
+		return err
+	}
+
+	var output Out
+	if err := json.Unmarshal(rec.Output, &output); err != nil {
+		return err
+	}
+
+	var controlErr error
+	if rec.Error != "" {
+		controlErr = errors.New(rec.Error)
+	}
+
+	e := scientist.NewTyped[Out](rec.Experiment)
+	for key, value := range rec.Context {
+		e.Context[key] = value
+	}
+	e.Use(func() (Out, error) {
+		return output, controlErr
+	})
+	setup(e, input)
+
+	e.Run()
+	return nil
+}
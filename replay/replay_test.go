@@ -0,0 +1,145 @@
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+type widget struct {
+	ID    int    `json:"id"`
+	Owner string `json:"owner"`
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+
+	widgets := []widget{{1, "alice"}, {2, "bob"}, {3, "carol"}}
+	for _, w := range widgets {
+		w := w
+		e := scientist.New("replay-widgets")
+		e.Context["region"] = "east"
+		e.Use(func() (interface{}, error) {
+			if w.ID == 3 {
+				return "", errors.New("not found")
+			}
+			return strings.ToUpper(w.Owner), nil
+		})
+		e.Try(func() (interface{}, error) {
+			return "skipped in production", nil
+		})
+		e.AfterRun(rec.Record(w))
+		e.Run()
+	}
+
+	// Recordings for other experiments are skipped.
+	other := scientist.New("replay-other")
+	other.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	other.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	other.AfterRun(rec.Record(nil))
+	other.Run()
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 4 {
+		t.Fatalf("Expected 4 records, got %d:\n%s", lines, buf.String())
+	}
+
+	var results []scientist.Result
+	var inputs []widget
+	err := Replay(&buf, "replay-widgets", func(e *scientist.TypedExperiment[string], w widget) {
+		inputs = append(inputs, w)
+		if e.Context["region"] != "east" {
+			t.Errorf("Unexpected context: %v", e.Context)
+		}
+
+		e.Try(func() (string, error) {
+			if w.ID == 3 {
+				return "", errors.New("not found")
+			}
+			if w.Owner == "bob" {
+				return "bob", nil
+			}
+			return strings.ToUpper(w.Owner), nil
+		})
+		e.Publish(func(r scientist.Result) error {
+			results = append(results, r)
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(inputs) != 3 || inputs[1] != widgets[1] {
+		t.Fatalf("Unexpected inputs: %+v", inputs)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if mismatched := r.IsMismatched(); mismatched != (i == 1) {
+			t.Errorf("Unexpected mismatch for %+v: %v", widgets[i], r.Candidates[0].Diff)
+		}
+	}
+	if v := results[1].Control.Value; v != "BOB" {
+		t.Errorf("Unexpected recorded control value: %v", v)
+	}
+	if err := results[2].Control.Err; err == nil || err.Error() != "not found" {
+		t.Errorf("Unexpected recorded control error: %v", err)
+	}
+}
+
+func TestReplayFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	for i := 0; i < 2; i++ {
+		rec, err := Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		e := scientist.New("replay-file")
+		e.Use(func() (interface{}, error) {
+			return i, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return i, nil
+		})
+		e.AfterRun(rec.Record(i))
+		e.Run()
+		rec.Close()
+	}
+
+	var inputs []int
+	err := ReplayFile(path, "replay-file", func(e *scientist.TypedExperiment[int], input int) {
+		inputs = append(inputs, input)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(inputs) != 2 || inputs[0] != 0 || inputs[1] != 1 {
+		t.Errorf("Unexpected inputs: %v", inputs)
+	}
+}
+
+func TestReplayBadRecord(t *testing.T) {
+	r := strings.NewReader("{\"experiment\":\"replay-bad\",\"input\":1,\"output\":2}\n{bad json\n")
+	runs := 0
+	err := Replay(r, "replay-bad", func(e *scientist.TypedExperiment[int], input int) {
+		runs++
+	})
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if runs != 1 {
+		t.Errorf("Expected 1 replayed run, got %d", runs)
+	}
+}
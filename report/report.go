@@ -0,0 +1,168 @@
+// Package report writes human-readable reports from experiment summaries, to
+// paste into a pull request or share with a team.
+//
+//	agg := scientist.NewAggregator(5)
+//	experiment.Publish(agg.Publish)
+//	// ... run the experiment
+//	report.Markdown(os.Stdout, agg.Summary())
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"scientist"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Summarize aggregates a set of results, keeping the top maxExamples
+// mismatches for each experiment.
+func Summarize(maxExamples int, results ...scientist.Result) []scientist.Summary {
+	agg := scientist.NewAggregator(maxExamples)
+	for _, r := range results {
+		agg.Publish(r)
+	}
+	return agg.Summary()
+}
+
+func Markdown(w io.Writer, summaries []scientist.Summary) error {
+	var b strings.Builder
+	for i, s := range summaries {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "## %s\n\n", s.Experiment)
+		b.WriteString("| Runs | Matched | Mismatched | Ignored | Skipped |\n")
+		b.WriteString("| ---: | ---: | ---: | ---: | ---: |\n")
+		fmt.Fprintf(&b, "| %d | %d (%s) | %d (%s) | %d | %d |\n\n",
+			s.Runs, s.Matched, percent(s.MatchRate), s.Mismatched, percent(s.MismatchRate), s.Ignored, s.Skipped)
+
+		b.WriteString("| Behavior | Runs | p50 | p95 | p99 |\n")
+		b.WriteString("| --- | ---: | ---: | ---: | ---: |\n")
+		for _, name := range behaviors(s) {
+			r := s.Behaviors[name]
+			fmt.Fprintf(&b, "| %s | %d | %s | %s | %s |\n", label(s, name), r.Count, duration(r.P50), duration(r.P95), duration(r.P99))
+		}
+
+		if len(s.Mismatches) > 0 {
+			b.WriteString("\n### Mismatches\n")
+			for _, m := range s.Mismatches {
+				fmt.Fprintf(&b, "\n**%s** mismatched %s:\n\n```diff\n%s\n```\n", m.Candidate, times(m.Count), strings.TrimRight(m.Diff, "\n"))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// CSV writes a row for each behavior of each experiment. Runtimes are in
+// milliseconds.
+func CSV(w io.Writer, summaries []scientist.Summary) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"experiment", "behavior", "control", "runs", "p50_ms", "p95_ms", "p99_ms",
+		"experiment_runs", "matched", "mismatched", "ignored", "skipped", "match_rate", "mismatch_rate",
+	})
+
+	for _, s := range summaries {
+		for _, name := range behaviors(s) {
+			r := s.Behaviors[name]
+			cw.Write([]string{
+				s.Experiment, name, strconv.FormatBool(name == s.Control), strconv.Itoa(r.Count),
+				millis(r.P50), millis(r.P95), millis(r.P99),
+				strconv.Itoa(s.Runs), strconv.Itoa(s.Matched), strconv.Itoa(s.Mismatched),
+				strconv.Itoa(s.Ignored), strconv.Itoa(s.Skipped),
+				strconv.FormatFloat(s.MatchRate, 'f', -1, 64), strconv.FormatFloat(s.MismatchRate, 'f', -1, 64),
+			})
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"behaviors": behaviors,
+	"label":     label,
+	"percent":   percent,
+	"duration":  duration,
+	"times":     times,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Experiment report</title></head>
+<body>
+{{- range $s := . }}
+<h2>{{ $s.Experiment }}</h2>
+<table>
+<tr><th>Runs</th><th>Matched</th><th>Mismatched</th><th>Ignored</th><th>Skipped</th></tr>
+<tr><td>{{ $s.Runs }}</td><td>{{ $s.Matched }} ({{ percent $s.MatchRate }})</td><td>{{ $s.Mismatched }} ({{ percent $s.MismatchRate }})</td><td>{{ $s.Ignored }}</td><td>{{ $s.Skipped }}</td></tr>
+</table>
+<table>
+<tr><th>Behavior</th><th>Runs</th><th>p50</th><th>p95</th><th>p99</th></tr>
+{{- range $name := behaviors $s }}{{ with index $s.Behaviors $name }}
+<tr><td>{{ label $s $name }}</td><td>{{ .Count }}</td><td>{{ duration .P50 }}</td><td>{{ duration .P95 }}</td><td>{{ duration .P99 }}</td></tr>
+{{- end }}{{ end }}
+</table>
+{{- if $s.Mismatches }}
+<h3>Mismatches</h3>
+{{- range $s.Mismatches }}
+<p><strong>{{ .Candidate }}</strong> mismatched {{ times .Count }}:</p>
+<pre>{{ .Diff }}</pre>
+{{- end }}
+{{- end }}
+{{- end }}
+</body>
+</html>
+`))
+
+func HTML(w io.Writer, summaries []scientist.Summary) error {
+	return htmlReport.Execute(w, summaries)
+}
+
+// behaviors lists the control first, then the candidates by name.
+func behaviors(s scientist.Summary) []string {
+	names := make([]string, 0, len(s.Behaviors))
+	for name := range s.Behaviors {
+		if name != s.Control {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if _, ok := s.Behaviors[s.Control]; ok {
+		names = append([]string{s.Control}, names...)
+	}
+	return names
+}
+
+func label(s scientist.Summary, name string) string {
+	if name == s.Control {
+		return name + " (control)"
+	}
+	return name
+}
+
+func percent(rate float64) string {
+	return strconv.FormatFloat(rate*100, 'f', 1, 64) + "%"
+}
+
+func duration(d time.Duration) string {
+	return d.Round(time.Microsecond).String()
+}
+
+func millis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
+
+func times(n int) string {
+	if n == 1 {
+		return "once"
+	}
+	return strconv.Itoa(n) + " times"
+}
@@ -0,0 +1,106 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+func summaries(t *testing.T) []scientist.Summary {
+	var results []scientist.Result
+	for i := 0; i < 3; i++ {
+		e := scientist.New("report")
+		e.Use(func() (interface{}, error) {
+			return 1, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return 2, nil
+		})
+		e.Behavior("correct", func() (interface{}, error) {
+			return 1, nil
+		})
+		e.Publish(func(r scientist.Result) error {
+			results = append(results, r)
+			return nil
+		})
+		e.Run()
+	}
+
+	s := Summarize(5, results...)
+	if len(s) != 1 {
+		t.Fatalf("Unexpected summaries: %+v", s)
+	}
+	return s
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Markdown(&buf, summaries(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"## report\n",
+		"| 3 | 0 (0.0%) | 3 (100.0%) | 0 | 0 |",
+		"| control (control) | 3 |",
+		"**candidate** mismatched 3 times:",
+		"```diff\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in report:\n%s", expected, out)
+		}
+	}
+
+	if strings.Index(out, "| control (control)") > strings.Index(out, "| candidate |") {
+		t.Errorf("Expected control before candidates:\n%s", out)
+	}
+	if strings.Contains(out, "**correct**") {
+		t.Errorf("Unexpected mismatch for correct candidate:\n%s", out)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := CSV(&buf, summaries(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("Unexpected rows: %v", rows)
+	}
+
+	for i, name := range []string{"control", "candidate", "correct"} {
+		row := rows[i+1]
+		if row[0] != "report" || row[1] != name || row[3] != "3" {
+			t.Errorf("Unexpected %s row: %v", name, row)
+		}
+	}
+	if rows[1][2] != "true" || rows[2][2] != "false" {
+		t.Errorf("Unexpected control columns: %v", rows)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := HTML(&buf, summaries(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"<h2>report</h2>",
+		"<td>control (control)</td>",
+		"<strong>candidate</strong> mismatched 3 times",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in report:\n%s", expected, out)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package scientist
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// RetryPolicy configures RetryPublisher.
+type RetryPolicy struct {
+	// Retries is how many more times a failed publish is tried.
+	Retries int
+
+	// Backoff is how long to wait before the first retry. It doubles for
+	// each retry after that, up to MaxBackoff if it's set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// DeadLetter is a file that results are appended to as JSON lines when
+	// every retry fails. Read them back with ReadDeadLetters.
+	DeadLetter string
+}
+
+// RetryPublisher wraps a publisher, retrying failed results with exponential
+// backoff. Retries wait inline, so wrap the returned publisher in a
+// PublishQueue to keep them off the experiment's run.
+//
+// The last error is still returned when every retry fails, so it's reported
+// as a publish error even if the result was saved to the dead letter file.
+func RetryPublisher(publisher func(Result) error, policy RetryPolicy) func(Result) error {
+	var mu sync.Mutex
+	return func(r Result) error {
+		err := publisher(r)
+		backoff := policy.Backoff
+		for i := 0; err != nil && i < policy.Retries; i++ {
+			time.Sleep(backoff)
+			err = publisher(r)
+
+			backoff *= 2
+			if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+				backoff = policy.MaxBackoff
+			}
+		}
+
+		if err == nil || policy.DeadLetter == "" {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if dlErr := appendDeadLetter(policy.DeadLetter, r); dlErr != nil {
+			return errors.Join(err, fmt.Errorf("[scientist] dead letter: %w", dlErr))
+		}
+		return err
+	}
+}
+
+func appendDeadLetter(path string, r Result) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadDeadLetters reads the results saved by RetryPublisher, in the order
+// they failed, so they can be published again.
+func ReadDeadLetters(r io.Reader) ([]ResultPayload, error) {
+	var payloads []ResultPayload
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var p ResultPayload
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			return payloads, err
+		}
+		payloads = append(payloads, p)
+	}
+
+	return payloads, scanner.Err()
+}
@@ -0,0 +1,90 @@
+package scientist
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRetryPublisher(t *testing.T) {
+	calls := 0
+	publish := RetryPublisher(func(r Result) error {
+		calls++
+		if calls < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	}, RetryPolicy{Retries: 3, Backoff: time.Millisecond})
+
+	e := New("retry")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Publish(publish)
+	e.ReportErrors(func(errs ...ResultError) {
+		t.Errorf("Unexpected errors: %v", errs)
+	})
+	e.Run()
+
+	if calls != 3 {
+		t.Errorf("Expected 3 publish attempts, got %d", calls)
+	}
+}
+
+func TestRetryPublisherDeadLetter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dead.jsonl")
+	calls := 0
+	publish := RetryPublisher(func(r Result) error {
+		calls++
+		return errors.New("unavailable")
+	}, RetryPolicy{Retries: 2, Backoff: time.Millisecond, DeadLetter: path})
+
+	var reported []ResultError
+	for i := 0; i < 2; i++ {
+		e := New("retry-dead-letter")
+		e.Use(func() (interface{}, error) {
+			return 1, nil
+		})
+		e.Try(func() (interface{}, error) {
+			return 2, nil
+		})
+		e.Publish(publish)
+		e.ReportErrors(func(errs ...ResultError) {
+			reported = append(reported, errs...)
+		})
+		e.Run()
+	}
+
+	if calls != 6 {
+		t.Errorf("Expected 6 publish attempts, got %d", calls)
+	}
+
+	if len(reported) != 2 || reported[0].Operation != "publish" || reported[0].Err.Error() != "unavailable" {
+		t.Errorf("Unexpected errors: %v", reported)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	payloads, err := ReadDeadLetters(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(payloads) != 2 {
+		t.Fatalf("Expected 2 dead letters, got %d", len(payloads))
+	}
+
+	p := payloads[0]
+	if p.Experiment != "retry-dead-letter" || !p.Mismatched || len(p.Candidates) != 1 {
+		t.Errorf("Unexpected dead letter: %+v", p)
+	}
+}
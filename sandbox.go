@@ -0,0 +1,45 @@
+package scientist
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrSandboxSkipped = errors.New("[scientist] sandbox didn't run the candidate")
+
+// Sandbox wraps each candidate run, to undo its side effects. The function
+// must call run once, with a context for the candidate, like one holding a
+// transaction that's rolled back afterwards. If it fails before calling run,
+// the error is the candidate's error. Later errors are reported with a
+// "sandbox" operation.
+func (e *Experiment) Sandbox(fn func(ctx context.Context, run func(ctx context.Context) error) error) {
+	if !e.callback("Sandbox", fn) {
+		return
+	}
+
+	e.sandbox = fn
+}
+
+func sandboxed(e *Experiment, name string, b behaviorFunc) behaviorFunc {
+	return func(ctx context.Context) (value interface{}, err error) {
+		ran := false
+		sandboxErr := e.sandbox(ctx, func(ctx context.Context) error {
+			ran = true
+			value, err = callBehavior(ctx, b)
+			return err
+		})
+
+		if !ran {
+			if sandboxErr == nil {
+				sandboxErr = ErrSandboxSkipped
+			}
+			return nil, sandboxErr
+		}
+
+		// Sandboxes can return the candidate's error from run.
+		if sandboxErr != nil && sandboxErr != err {
+			e.report(e.behaviorErr("sandbox", name, sandboxErr))
+		}
+		return value, err
+	}
+}
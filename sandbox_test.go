@@ -0,0 +1,127 @@
+package scientist
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type sandboxKey struct{}
+
+func TestSandbox(t *testing.T) {
+	var rolledBack []string
+	e := New("sandbox")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		return ctx.Value(sandboxKey{}), nil
+	})
+	e.Sandbox(func(ctx context.Context, run func(ctx context.Context) error) error {
+		run(context.WithValue(ctx, sandboxKey{}, 1))
+		rolledBack = append(rolledBack, "candidate")
+		return nil
+	})
+
+	var result Result
+	e.Publish(func(r Result) error {
+		result = r
+		return nil
+	})
+	e.ReportErrors(func(errs ...ResultError) {
+		t.Errorf("Unexpected errors: %v", errs)
+	})
+
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control: %v, %v", v, err)
+	}
+
+	if !result.IsMatched() {
+		t.Errorf("Expected candidate to see the sandbox context:\n%s", result.Diff())
+	}
+	if len(rolledBack) != 1 {
+		t.Errorf("Expected the sandbox to wrap only the candidate, got %v", rolledBack)
+	}
+}
+
+func TestSandboxErrors(t *testing.T) {
+	begin := errors.New("begin failed")
+	rollback := errors.New("rollback failed")
+	candidateErr := errors.New("candidate failed")
+
+	tests := []struct {
+		name      string
+		sandbox   func(ctx context.Context, run func(ctx context.Context) error) error
+		candidate error
+		expected  error
+		reported  error
+	}{
+		{
+			name: "begin",
+			sandbox: func(ctx context.Context, run func(ctx context.Context) error) error {
+				return begin
+			},
+			expected: begin,
+		},
+		{
+			name: "skipped",
+			sandbox: func(ctx context.Context, run func(ctx context.Context) error) error {
+				return nil
+			},
+			expected: ErrSandboxSkipped,
+		},
+		{
+			name: "rollback",
+			sandbox: func(ctx context.Context, run func(ctx context.Context) error) error {
+				run(ctx)
+				return rollback
+			},
+			reported: rollback,
+		},
+		{
+			name: "candidate",
+			sandbox: func(ctx context.Context, run func(ctx context.Context) error) error {
+				return run(ctx)
+			},
+			candidate: candidateErr,
+			expected:  candidateErr,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New("sandbox-" + test.name)
+			e.Use(func() (interface{}, error) {
+				return 1, nil
+			})
+			e.Try(func() (interface{}, error) {
+				return 1, test.candidate
+			})
+			e.Sandbox(test.sandbox)
+
+			var result Result
+			e.Publish(func(r Result) error {
+				result = r
+				return nil
+			})
+
+			var reported []ResultError
+			e.ReportErrors(func(errs ...ResultError) {
+				reported = append(reported, errs...)
+			})
+			e.Run()
+
+			if err := result.Candidates[0].Err; err != test.expected {
+				t.Errorf("Unexpected candidate error: %v", err)
+			}
+
+			if test.reported == nil {
+				if len(reported) != 0 {
+					t.Errorf("Unexpected reported errors: %v", reported)
+				}
+			} else if len(reported) != 1 || reported[0].Operation != "sandbox" || reported[0].Err != test.reported {
+				t.Errorf("Unexpected reported errors: %v", reported)
+			}
+		})
+	}
+}
@@ -1,8 +1,16 @@
 package scientist
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -11,18 +19,46 @@ const (
 )
 
 type Observation struct {
-	Experiment *Experiment
-	Name       string
-	Started    time.Time
-	Runtime    time.Duration
-	Value      interface{}
-	Err        error
+	Experiment   *Experiment
+	Name         string
+	Started      time.Time
+	Runtime      time.Duration
+	Value        interface{}
+	Err          error
+	SkipReason   string
+	IgnoreReason string
+	Diff         string
+	Cleaned      interface{}
+	CleanErr     error
+	AllocBytes   uint64
+	Allocs       uint64
+	Slowdown     float64
+	Unstable     bool
+	TimedOut     bool
+	Version      string
+	Tags         map[string]string
+	cleaned      bool
+	abandoned    bool
+
+	// returned is what Run returns instead of Value when substituted is set,
+	// like the real value behind a Hash.
+	substituted bool
+	returned    interface{}
 }
 
 func (o *Observation) CleanedValue() (interface{}, error) {
+	if o.cleaned {
+		return o.Cleaned, o.CleanErr
+	}
 	return o.Experiment.cleaner(o.Value)
 }
 
+func (o *Observation) clean() error {
+	o.Cleaned, o.CleanErr = o.Experiment.cleaner(o.Value)
+	o.cleaned = true
+	return o.CleanErr
+}
+
 type Result struct {
 	Experiment   *Experiment
 	Control      *Observation
@@ -30,11 +66,26 @@ type Result struct {
 	Candidates   []*Observation
 	Ignored      []*Observation
 	Mismatched   []*Observation
+	Skipped      []*Observation
+	TimedOut     []*Observation
 	Errors       []ResultError
+	SkipReason   string
+	Key          string
+	Bucket       float64
+	Variant      string
+	Data         map[string]interface{}
+	ctx          context.Context
+}
+
+func (r Result) Context() context.Context {
+	if r.ctx == nil {
+		return context.Background()
+	}
+	return r.ctx
 }
 
 func (r Result) IsMatched() bool {
-	if r.IsMismatched() || r.IsIgnored() {
+	if r.SkipReason != "" || r.Variant != "" || r.IsMismatched() || r.IsIgnored() {
 		return false
 	}
 	return true
@@ -48,35 +99,213 @@ func (r Result) IsIgnored() bool {
 	return len(r.Ignored) > 0
 }
 
+// published returns a copy of the result with scrubbed and truncated
+// observation values. The original observations are left alone, since the
+// caller still needs the real control value.
+func (r Result) published() Result {
+	e := r.Experiment
+	if e.scrubber == nil && e.maxValueSize <= 0 {
+		return r
+	}
+
+	copies := make(map[*Observation]*Observation, len(r.Observations)+len(r.Skipped))
+	scrubAll := func(obs []*Observation) []*Observation {
+		if obs == nil {
+			return nil
+		}
+
+		scrubbed := make([]*Observation, len(obs))
+		for i, o := range obs {
+			c, ok := copies[o]
+			if !ok {
+				copied := *o
+				copied.Value = e.publishedValue(o.Value)
+				if copied.cleaned && copied.CleanErr == nil {
+					copied.Cleaned = e.publishedValue(o.Cleaned)
+				}
+				c = &copied
+				copies[o] = c
+			}
+			scrubbed[i] = c
+		}
+		return scrubbed
+	}
+
+	if r.Control != nil {
+		r.Control = scrubAll([]*Observation{r.Control})[0]
+	}
+	r.Observations = scrubAll(r.Observations)
+	r.Candidates = scrubAll(r.Candidates)
+	r.Ignored = scrubAll(r.Ignored)
+	r.Mismatched = scrubAll(r.Mismatched)
+	r.Skipped = scrubAll(r.Skipped)
+	r.TimedOut = scrubAll(r.TimedOut)
+	return r
+}
+
 func Run(e *Experiment, name string) Result {
-	r := Result{Experiment: e}
-	if err := e.beforeRun(); err != nil {
-		r.Errors = append(r.Errors, e.resultErr("before_run", err))
+	return run(e, name, runOptions{ctx: context.Background()})
+}
+
+func run(e *Experiment, name string, opts runOptions) Result {
+	return conclude(observeRun(e, name, opts))
+}
+
+// observeRun observes the control and candidates, without comparing them.
+func observeRun(e *Experiment, name string, opts runOptions) Result {
+	r := start(e, opts)
+
+	ctx := withControlRuntime(opts.ctx, e)
+	shed := e.shedding()
+	if !shed {
+		warmUp(opts.ctx, e, name)
 	}
 
-	numCandidates := len(e.behaviors) - 1
-	r.Control = observe(e, name, e.behaviors[name])
-	r.Candidates = make([]*Observation, numCandidates)
-	r.Ignored = make([]*Observation, 0, numCandidates)
-	r.Mismatched = make([]*Observation, 0, numCandidates)
-	r.Observations = make([]*Observation, numCandidates+1)
-	r.Observations[0] = r.Control
+	if shed {
+		r.Control = observeControl(ctx, e, name, e.controlTimes)
+		r.Candidates = shedCandidates(e, name)
+	} else if e.concurrent && !e.controlFirst {
+		r.Control, r.Candidates = observeConcurrently(ctx, e, name)
+	} else {
+		r.Control = observeControl(ctx, e, name, e.controlTimes)
+		r.Candidates = observeCandidates(ctx, e, name)
+	}
 
-	i := 0
-	for bname, b := range e.behaviors {
-		if bname == name {
-			continue
+	return r
+}
+
+// runAsyncComparison observes every behavior before it returns, but returns a
+// result with a copy of the control. The candidates are compared, cleaned, and
+// published in the background, which writes to the original observations.
+func runAsyncComparison(e *Experiment, name string, opts runOptions) Result {
+	r := observeRun(e, name, opts)
+	control := *r.Control
+	partial := r
+	partial.Control = &control
+	partial.Candidates = nil
+
+	background.add(1)
+	go func() {
+		defer background.add(-1)
+		conclude(r)
+	}()
+
+	return partial
+}
+
+// runAsync returns a result with only the control as soon as it's available.
+// The candidates are observed, compared, and published in the background.
+func runAsync(e *Experiment, name string, opts runOptions) Result {
+	r := start(e, opts)
+
+	ctx := withControlRuntime(opts.ctx, e)
+	shed := e.shedding()
+	if !shed {
+		warmUp(opts.ctx, e, name)
+	}
+	r.Control = observeControl(ctx, e, name, e.controlTimes)
+
+	// conclude cleans and versions the control in the background, so the
+	// caller gets its own copy.
+	control := *r.Control
+	partial := r
+	partial.Control = &control
+	background.add(1)
+	go func() {
+		defer background.add(-1)
+		if shed {
+			r.Candidates = shedCandidates(e, name)
+		} else {
+			// candidates outlive the caller, so they keep its values but not
+			// its cancellation.
+			r.Candidates = observeCandidates(context.WithoutCancel(ctx), e, name)
 		}
+		conclude(r)
+	}()
+
+	return partial
+}
+
+// runSkipped observes only the control, and publishes it with a skip reason
+// so the skipped runs still show up.
+func runSkipped(e *Experiment, name string, opts runOptions, reason string) Result {
+	r := newResult(e, opts)
+	r.SkipReason = reason
+	r.Control = observeControl(opts.ctx, e, name, 1)
+	r.Observations = []*Observation{r.Control}
+	r = intercept(r)
+
+	e.state.record(r)
+	r = publish(r)
+	if len(r.Errors) > 0 {
+		e.report(r.Errors...)
+	}
+
+	return r
+}
 
-		c := observe(e, bname, b)
-		r.Candidates[i] = c
-		i += 1
-		r.Observations[i] = c
+func newResult(e *Experiment, opts runOptions) Result {
+	r := Result{Experiment: e, Data: opts.data, ctx: opts.ctx}
+	if opts.keyed {
+		r.Key = opts.key
+		r.Bucket = bucket(e.Name, opts.key)
+	}
+	return r
+}
+
+func start(e *Experiment, opts runOptions) Result {
+	r := newResult(e, opts)
+	for _, fn := range e.beforeRuns {
+		if err := fn(); err != nil {
+			r.Errors = append(r.Errors, e.resultErr("before_run", err))
+		}
+	}
+
+	return r
+}
+
+func conclude(r Result) Result {
+	e := r.Experiment
+	recordBreakers(e, r.Candidates)
+	if e.versions != nil {
+		r.Control.Version = e.versions[r.Control.Name]
+		for _, c := range r.Candidates {
+			c.Version = e.versions[c.Name]
+		}
+	}
+
+	candidates := r.Candidates[:0]
+	for _, c := range r.Candidates {
+		if c.SkipReason != "" {
+			r.Skipped = append(r.Skipped, c)
+		} else {
+			candidates = append(candidates, c)
+		}
+	}
+	r.Candidates = candidates
+	for _, c := range r.Candidates {
+		if c.TimedOut {
+			r.TimedOut = append(r.TimedOut, c)
+		}
+	}
+
+	numCandidates := len(r.Candidates)
+	r.Observations = make([]*Observation, numCandidates+1)
+	r.Observations[0] = r.Control
+	copy(r.Observations[1:], r.Candidates)
+
+	for _, c := range r.Candidates {
+		if c.Runtime > 0 && r.Control.Runtime > 0 {
+			c.Slowdown = float64(c.Runtime) / float64(r.Control.Runtime)
+			if e.maxSlowdown > 0 && c.Slowdown > e.maxSlowdown {
+				r.Errors = append(r.Errors, e.behaviorErr("performance", c.Name, fmt.Errorf("[scientist] candidate %q took %.1fx as long as the control", c.Name, c.Slowdown)))
+			}
+		}
 
 		ok, err := matching(e, r.Control, c)
 		if err != nil {
 			ok = false
-			r.Errors = append(r.Errors, e.resultErr("compare", err))
+			r.Errors = append(r.Errors, e.behaviorErr("compare", c.Name, err))
 		}
 
 		if ok {
@@ -86,28 +315,320 @@ func Run(e *Experiment, name string) Result {
 		ignored, err := ignoring(e, r.Control, c)
 		if err != nil {
 			ignored = false
-			r.Errors = append(r.Errors, e.resultErr("ignore", err))
+			r.Errors = append(r.Errors, e.behaviorErr("ignore", c.Name, err))
 		}
 
 		if ignored {
 			r.Ignored = append(r.Ignored, c)
 		} else {
+			c.Diff = diff(e, r.Control, c)
 			r.Mismatched = append(r.Mismatched, c)
 		}
 	}
 
-	if err := e.publisher(r); err != nil {
-		r.Errors = append(r.Errors, e.resultErr("publish", err))
+	r = afterRun(r)
+	r = intercept(r)
+
+	e.state.record(r)
+	if e.adaptive != nil {
+		e.state.adaptive.record(e.adaptive, r.IsMismatched())
 	}
 
+	r = publish(r)
+
 	if len(r.Errors) > 0 {
-		e.errorReporter(r.Errors...)
+		e.report(r.Errors...)
 	}
 
 	return r
 }
 
+func afterRun(r Result) Result {
+	e := r.Experiment
+	for _, fn := range e.afterRuns {
+		if err := fn(r); err != nil {
+			r.Errors = append(r.Errors, e.resultErr("after_run", err))
+		}
+	}
+	return r
+}
+
+func publish(r Result) Result {
+	e := r.Experiment
+	if !e.sampled(r) {
+		return r
+	}
+
+	for _, o := range r.Observations {
+		if err := o.clean(); err != nil {
+			r.Errors = append(r.Errors, e.behaviorErr("clean", o.Name, err))
+		}
+	}
+
+	if e.duplicate(r) {
+		return r
+	}
+
+	published := r.published()
+	if err := e.publisher(published); err != nil {
+		r.Errors = append(r.Errors, e.resultErr("publish", err))
+	}
+
+	if r.IsMismatched() {
+		for _, fn := range e.mismatchHandlers {
+			if err := fn(published); err != nil {
+				r.Errors = append(r.Errors, e.resultErr("mismatch", err))
+			}
+		}
+	}
+
+	return r
+}
+
+// observeControl observes the control, and runs it again until it's run the
+// given number of times, to check that it's stable.
+func observeControl(ctx context.Context, e *Experiment, name string, times int) *Observation {
+	b := e.behaviors[name]
+	control := observe(controlContext(ctx, e), e, name, b)
+	finishControl(ctx, e, control)
+	checkControlPanic(e, control)
+	repeatObservation(controlContext(ctx, e), e, control, b, times)
+	cloneObservation(e, control, true)
+	hashObservation(e, control, true)
+	return control
+}
+
+func observeCandidates(ctx context.Context, e *Experiment, name string) []*Observation {
+	if e.concurrent {
+		return observeCandidatesConcurrently(ctx, e, name)
+	}
+
+	candidates := make([]*Observation, 0, len(e.behaviors)-1)
+	for _, bname := range behaviorNames(e) {
+		if bname == name {
+			continue
+		}
+
+		o := observeCandidateSequentially(ctx, e, bname, e.behaviors[bname])
+		if o.abandoned && e.aborter != nil {
+			e.aborter(o.Name)
+		}
+		candidates = append(candidates, o)
+	}
+
+	return candidates
+}
+
+func observeConcurrently(ctx context.Context, e *Experiment, name string) (*Observation, []*Observation) {
+	var g errgroup.Group
+	var control *Observation
+	g.Go(func() error {
+		control = observe(controlContext(ctx, e), e, name, e.behaviors[name])
+		finishControl(ctx, e, control)
+		if _, panicked := control.Err.(PanicError); !panicked {
+			repeatObservation(controlContext(ctx, e), e, control, e.behaviors[name], e.controlTimes)
+		}
+		return nil
+	})
+
+	candidates := goCandidates(&g, ctx, e, name)
+	g.Wait()
+
+	checkControlPanic(e, control)
+	cloneObservation(e, control, true)
+	hashObservation(e, control, true)
+	return control, candidates
+}
+
+func observeCandidatesConcurrently(ctx context.Context, e *Experiment, name string) []*Observation {
+	var g errgroup.Group
+	candidates := goCandidates(&g, ctx, e, name)
+	g.Wait()
+	return candidates
+}
+
+// goCandidates starts every candidate in g. Each one writes its observation to
+// its own slot, so candidates are in the same order every run.
+func goCandidates(g *errgroup.Group, ctx context.Context, e *Experiment, name string) []*Observation {
+	candidates := make([]*Observation, len(e.behaviors)-1)
+
+	var abortMu sync.Mutex
+	next := 0
+	for _, bname := range behaviorNames(e) {
+		if bname == name {
+			continue
+		}
+
+		i, bname := next, bname
+		next++
+		g.Go(func() error {
+			o := observeCandidateWithin(ctx, e, bname, e.behaviors[bname])
+			if o.abandoned && e.aborter != nil {
+				abortMu.Lock()
+				e.aborter(bname)
+				abortMu.Unlock()
+			}
+			candidates[i] = o
+			return nil
+		})
+	}
+
+	return candidates
+}
+
+// behaviorNames returns the names of every behavior, sorted, so candidates
+// are in the same order every run.
+func behaviorNames(e *Experiment) []string {
+	if atomic.LoadInt32(&e.validation.sealed) == 1 {
+		return e.validation.names
+	}
+	return sortedNames(e.behaviors)
+}
+
+// profileLabels returns the pprof labels for a behavior.
+func profileLabels(e *Experiment, name string) pprof.LabelSet {
+	if atomic.LoadInt32(&e.validation.sealed) == 1 {
+		if labels, ok := e.validation.labels[name]; ok {
+			return labels
+		}
+	}
+	return pprof.Labels("experiment", e.Name, "behavior", name)
+}
+
+// observeCandidateWithin stops waiting for a candidate once its context is
+// done. The abandoned candidate still gets a partial observation with when it
+// started and how long it ran.
+func observeCandidateWithin(ctx context.Context, e *Experiment, name string, b behaviorFunc) *Observation {
+	ctx, cancel := candidateContext(ctx, e, name)
+	defer cancel()
+
+	ctx, cancelSlow := slowContext(ctx, e)
+	defer cancelSlow()
+
+	started := time.Now()
+	if ctx.Err() != nil {
+		return abandon(ctx, e, name, started)
+	}
+
+	candidateCh := make(chan *Observation, 1)
+	go func() {
+		candidateCh <- observeCandidate(ctx, e, name, b)
+	}()
+
+	select {
+	case o := <-candidateCh:
+		return o
+	case <-ctx.Done():
+		return abandon(ctx, e, name, started)
+	}
+}
+
+// abandon records a candidate that was given up on because ctx is done.
+func abandon(ctx context.Context, e *Experiment, name string, started time.Time) *Observation {
+	err := context.Cause(ctx)
+	return &Observation{
+		Experiment: e,
+		Name:       name,
+		Started:    started,
+		Runtime:    time.Since(started),
+		Err:        err,
+		TimedOut:   errors.Is(err, context.DeadlineExceeded) || err == ErrSlowCandidate,
+		abandoned:  true,
+	}
+}
+
+// observeCandidateSequentially cancels a candidate's context after its own
+// timeout or its share of the control's runtime. Without concurrency, nothing
+// stops waiting for it, so the candidate has to give up on its own. Candidates
+// aren't started at all once the caller's context is done.
+func observeCandidateSequentially(ctx context.Context, e *Experiment, name string, b behaviorFunc) *Observation {
+	if ctx.Err() != nil {
+		return abandon(ctx, e, name, time.Now())
+	}
+
+	if d, ok := e.timeouts[name]; ok && d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	ctx, cancel := slowContext(ctx, e)
+	defer cancel()
+
+	return observeCandidate(ctx, e, name, b)
+}
+
+func observeCandidate(ctx context.Context, e *Experiment, name string, b behaviorFunc) *Observation {
+	if br := e.candidateBreaker(name); br != nil && !br.allow(time.Now()) {
+		return &Observation{
+			Experiment: e,
+			Name:       name,
+			Started:    time.Now(),
+			SkipReason: SkipTripped,
+		}
+	}
+
+	release, ok := acquireCandidate()
+	if !ok {
+		return &Observation{
+			Experiment: e,
+			Name:       name,
+			Started:    time.Now(),
+			SkipReason: SkipShed,
+		}
+	}
+
+	defer release()
+	if e.faults != nil {
+		b = faulty(e.faults, b)
+	}
+
+	if e.sandbox != nil {
+		b = sandboxed(e, name, b)
+	}
+
+	o := observe(ctx, e, name, b)
+	repeatObservation(ctx, e, o, b, e.candidateTimes)
+
+	cloneObservation(e, o, false)
+	hashObservation(e, o, false)
+	return o
+}
+
+// repeatObservation runs a behavior again until it's run the given number of
+// times, and marks the observation Unstable if a run doesn't match the first.
+func repeatObservation(ctx context.Context, e *Experiment, o *Observation, b behaviorFunc, times int) {
+	for i := 1; i < times && !o.Unstable; i++ {
+		repeat := observe(ctx, e, o.Name, b)
+		if ok, err := matching(e, o, repeat); err != nil || !ok {
+			o.Unstable = true
+		}
+	}
+}
+
+func checkControlPanic(e *Experiment, control *Observation) {
+	if pe, ok := control.Err.(PanicError); ok && !e.CaptureControlPanics {
+		panic(pe.Value)
+	}
+}
+
+func candidateContext(ctx context.Context, e *Experiment, name string) (context.Context, context.CancelFunc) {
+	timeout := e.timeout
+	if d, ok := e.timeouts[name]; ok {
+		timeout = d
+	}
+
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
 func matching(e *Experiment, control, candidate *Observation) (bool, error) {
+	if e.observationComparator != nil {
+		return e.observationComparator(control, candidate)
+	}
+
 	// neither returned errors
 	if control.Err == nil && candidate.Err == nil {
 		return e.comparator(control.Value, candidate.Value)
@@ -115,7 +636,7 @@ func matching(e *Experiment, control, candidate *Observation) (bool, error) {
 
 	// both returned errors
 	if control.Err != nil && candidate.Err != nil {
-		return control.Err.Error() == candidate.Err.Error(), nil
+		return e.errorComparator(control.Err, candidate.Err)
 	}
 
 	// returned different errors
@@ -124,12 +645,13 @@ func matching(e *Experiment, control, candidate *Observation) (bool, error) {
 
 func ignoring(e *Experiment, control, candidate *Observation) (bool, error) {
 	for _, i := range e.ignores {
-		ok, err := i(control.Value, candidate.Value)
+		ok, reason, err := i(control, candidate)
 		if err != nil {
 			return false, err
 		}
 
 		if ok {
+			candidate.IgnoreReason = reason
 			return true, nil
 		}
 	}
@@ -141,12 +663,25 @@ func behaviorNotFound(e *Experiment, name string) error {
 	return fmt.Errorf("Behavior %q not found for experiment %q", name, e.Name)
 }
 
-func observe(e *Experiment, name string, b behaviorFunc) *Observation {
-	o := &Observation{
+func observe(ctx context.Context, e *Experiment, name string, b behaviorFunc) *Observation {
+	tearDown, err := setUp(ctx, e, name, true)
+	if err != nil {
+		return &Observation{
+			Experiment: e,
+			Name:       name,
+			Started:    time.Now(),
+			Err:        err,
+		}
+	}
+	defer tearDown()
+
+	ob := &observation{Observation: Observation{
 		Experiment: e,
 		Name:       name,
 		Started:    time.Now(),
-	}
+	}}
+	o := &ob.Observation
+	ctx = withTags(ctx, &ob.tags)
 
 	if b == nil {
 		b = e.behaviors[name]
@@ -156,29 +691,97 @@ func observe(e *Experiment, name string, b behaviorFunc) *Observation {
 		o.Runtime = time.Since(o.Started)
 		o.Err = behaviorNotFound(e, name)
 	} else {
-		v, err := b()
+		if e.measureAllocs {
+			b = measuredAllocs(o, b)
+		}
+
+		v, err := instrumentedCall(ctx, e, name, b)
 		o.Runtime = time.Since(o.Started)
 		o.Value = v
 		o.Err = err
 	}
 
+	o.Tags = ob.tags.values()
 	return o
 }
 
+// instrumentedCall labels the behavior for profiles and execution traces.
+func instrumentedCall(ctx context.Context, e *Experiment, name string, b behaviorFunc) (value interface{}, err error) {
+	ctx, end := traceBehavior(ctx, e, name)
+	defer end()
+
+	pprof.Do(ctx, profileLabels(e, name), func(ctx context.Context) {
+		value, err = callBehavior(ctx, wrapped(e, name, b))
+	})
+	return value, err
+}
+
+func callBehavior(ctx context.Context, b behaviorFunc) (value interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			value = nil
+			err = PanicError{Value: p, Stack: debug.Stack()}
+		}
+	}()
+
+	return b(ctx)
+}
+
 type ResultError struct {
 	Operation  string
 	Experiment string
-	Err        error
+
+	// Behavior is set when a single behavior caused the error.
+	Behavior string
+	Severity Severity
+	Err      error
 }
 
 func (e ResultError) Error() string {
 	return e.Err.Error()
 }
 
+func (e ResultError) Unwrap() error {
+	return e.Err
+}
+
+var ErrMismatch = errors.New("[scientist] observations mismatched")
+
+// MismatchError is returned with ErrorOnMismatches when candidates mismatch.
+// It wraps ErrMismatch and the errors of the mismatched candidates.
 type MismatchError struct {
-	Result Result
+	Result     Result
+	Control    *Observation
+	Mismatched []*Observation
+}
+
+func newMismatchError(r Result) MismatchError {
+	return MismatchError{Result: r, Control: r.Control, Mismatched: r.Mismatched}
 }
 
 func (e MismatchError) Error() string {
 	return fmt.Sprintf("[scientist] experiment %q observations mismatched", e.Result.Experiment.Name)
 }
+
+func (e MismatchError) Diff() string {
+	return e.Result.Diff()
+}
+
+func (e MismatchError) Unwrap() []error {
+	errs := []error{ErrMismatch}
+	for _, o := range e.Mismatched {
+		if o.Err != nil {
+			errs = append(errs, o.Err)
+		}
+	}
+	return errs
+}
+
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e PanicError) Error() string {
+	return fmt.Sprintf("[scientist] behavior panicked: %v", e.Value)
+}
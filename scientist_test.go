@@ -1,12 +1,25 @@
 package scientist
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"testing"
 )
 
+var uniqueNames int64
+
+// uniqueName returns a new experiment name for each call, for tests that
+// depend on the state registered by name starting out empty. Otherwise, the
+// state leaks into the next run with go test -count.
+func uniqueName(name string) string {
+	return fmt.Sprintf("%s-%d", name, atomic.AddInt64(&uniqueNames, 1))
+}
+
 func basicExperiment() *Experiment {
 	e := New("basic")
 	e.Use(func() (interface{}, error) {
@@ -144,6 +157,48 @@ func TestCompareAndIgnore(t *testing.T) {
 	assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate"})
 }
 
+func TestIgnoreObservations(t *testing.T) {
+	e := basicExperiment()
+	e.Behavior("timeout", func() (interface{}, error) {
+		return nil, context.DeadlineExceeded
+	})
+	e.IgnoreObservations(func(control, candidate *Observation) (bool, error) {
+		return errors.Is(candidate.Err, context.DeadlineExceeded), nil
+	})
+	r := Run(e, "control")
+	if len(r.Errors) != 0 {
+		t.Errorf("Unexpected experiment errors: %v", r.Errors)
+	}
+
+	assertObservationNames(t, "ignored", r.Ignored, []string{"timeout"})
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate", "three"})
+}
+
+func TestIgnoreWithReason(t *testing.T) {
+	e := basicExperiment()
+	e.IgnoreWithReason(func(control, candidate *Observation) (bool, string, error) {
+		if candidate.Value == 3 {
+			return true, "three is close enough", nil
+		}
+		return false, "", nil
+	})
+	r := Run(e, "control")
+	if len(r.Errors) != 0 {
+		t.Errorf("Unexpected experiment errors: %v", r.Errors)
+	}
+
+	assertObservationNames(t, "ignored", r.Ignored, []string{"three"})
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate"})
+
+	if reason := r.Ignored[0].IgnoreReason; reason != "three is close enough" {
+		t.Errorf("Unexpected ignore reason: %q", reason)
+	}
+
+	if reason := r.Ignored[0].Payload().IgnoreReason; reason != "three is close enough" {
+		t.Errorf("Unexpected ignore reason in payload: %q", reason)
+	}
+}
+
 func TestDefaultCleaner(t *testing.T) {
 	e := New("cleaner")
 	e.Use(func() (interface{}, error) {
@@ -0,0 +1,114 @@
+// Package scientistcache compares reads from two cache backends, like when
+// moving from memcached to Redis. A miss only matches another miss, and TTLs
+// can be compared with some tolerance, since the backends rarely expire keys
+// at exactly the same time.
+package scientistcache
+
+import (
+	"context"
+	"reflect"
+	"scientist"
+	"time"
+)
+
+// IgnoreMissReason is the ignore reason for candidate misses when IgnoreMisses
+// is set.
+const IgnoreMissReason = "candidate miss"
+
+// Entry is the result of reading a key from a cache.
+type Entry struct {
+	Hit   bool
+	Value interface{}
+
+	// TTL is the time left before the key expires, or zero if it doesn't
+	// expire or the backend doesn't report it.
+	TTL time.Duration
+}
+
+// Cache reads a key from a cache backend.
+type Cache interface {
+	Get(ctx context.Context, key string) (Entry, error)
+}
+
+// CacheFunc adapts a function to a Cache.
+type CacheFunc func(ctx context.Context, key string) (Entry, error)
+
+func (fn CacheFunc) Get(ctx context.Context, key string) (Entry, error) {
+	return fn(ctx, key)
+}
+
+type CompareOptions struct {
+	// TTLTolerance is how much the TTLs of two hits may differ. TTLs are
+	// ignored if it's negative.
+	TTLTolerance time.Duration
+
+	// IgnoreMisses ignores results where the control hit and the candidate
+	// missed, like while a new cache is warming up.
+	IgnoreMisses bool
+}
+
+// New returns an experiment that reads the key from both caches. Each
+// behavior returns an Entry.
+func New(name, key string, control, candidate Cache, opts CompareOptions) *scientist.Experiment {
+	e := scientist.New(name)
+	e.Context["key"] = key
+
+	e.UseContext(func(ctx context.Context) (interface{}, error) {
+		return control.Get(ctx, key)
+	})
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		return candidate.Get(ctx, key)
+	})
+
+	e.Compare(opts.Compare)
+	if opts.IgnoreMisses {
+		e.IgnoreWithReason(ignoreMiss)
+	}
+
+	return e
+}
+
+// Compare compares two entries. Two misses always match.
+func (o CompareOptions) Compare(control, candidate interface{}) (bool, error) {
+	a, ok := control.(Entry)
+	if !ok {
+		return false, nil
+	}
+
+	b, ok := candidate.(Entry)
+	if !ok {
+		return false, nil
+	}
+
+	if !a.Hit || !b.Hit {
+		return a.Hit == b.Hit, nil
+	}
+
+	return reflect.DeepEqual(a.Value, b.Value) && o.ttlMatches(a.TTL, b.TTL), nil
+}
+
+func (o CompareOptions) ttlMatches(a, b time.Duration) bool {
+	if o.TTLTolerance < 0 {
+		return true
+	}
+
+	// Keys that don't expire only match each other.
+	if a == 0 || b == 0 {
+		return a == b
+	}
+
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= o.TTLTolerance
+}
+
+func ignoreMiss(control, candidate *scientist.Observation) (bool, string, error) {
+	a, _ := control.Value.(Entry)
+	b, _ := candidate.Value.(Entry)
+	if control.Err == nil && candidate.Err == nil && a.Hit && !b.Hit {
+		return true, IgnoreMissReason, nil
+	}
+	return false, "", nil
+}
@@ -0,0 +1,90 @@
+package scientistcache
+
+import (
+	"context"
+	"scientist"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	opts := CompareOptions{TTLTolerance: time.Second}
+
+	tests := []struct {
+		name      string
+		control   Entry
+		candidate Entry
+		matched   bool
+	}{
+		{"misses", Entry{}, Entry{Value: "stale"}, true},
+		{"hits", Entry{Hit: true, Value: "a", TTL: time.Minute}, Entry{Hit: true, Value: "a", TTL: time.Minute - time.Second}, true},
+		{"values", Entry{Hit: true, Value: "a"}, Entry{Hit: true, Value: "b"}, false},
+		{"miss", Entry{Hit: true, Value: "a"}, Entry{}, false},
+		{"ttl", Entry{Hit: true, Value: "a", TTL: time.Minute}, Entry{Hit: true, Value: "a", TTL: time.Minute - 2*time.Second}, false},
+		{"no expiration", Entry{Hit: true, Value: "a"}, Entry{Hit: true, Value: "a", TTL: time.Second}, false},
+	}
+
+	for _, test := range tests {
+		matched, err := opts.Compare(test.control, test.candidate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if matched != test.matched {
+			t.Errorf("Expected %s matched to be %v", test.name, test.matched)
+		}
+	}
+
+	opts.TTLTolerance = -1
+	if matched, _ := opts.Compare(tests[4].control, tests[4].candidate); !matched {
+		t.Errorf("Expected TTLs to be ignored")
+	}
+}
+
+func TestNew(t *testing.T) {
+	memcached := CacheFunc(func(ctx context.Context, key string) (Entry, error) {
+		return Entry{Hit: true, Value: []byte("widget:" + key)}, nil
+	})
+	redis := CacheFunc(func(ctx context.Context, key string) (Entry, error) {
+		if key == "cold" {
+			return Entry{}, nil
+		}
+		return Entry{Hit: true, Value: []byte("widget:" + key)}, nil
+	})
+
+	results := make(map[string]scientist.Result)
+	for _, key := range []string{"warm", "cold"} {
+		for _, ignore := range []bool{false, true} {
+			e := New("scientistcache", key, memcached, redis, CompareOptions{IgnoreMisses: ignore})
+			e.Publish(func(r scientist.Result) error {
+				if ignore {
+					key += "-ignored"
+				}
+				results[key] = r
+				return nil
+			})
+
+			v, err := e.Run()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if entry := v.(Entry); !entry.Hit {
+				t.Errorf("Unexpected control entry: %+v", entry)
+			}
+		}
+	}
+
+	if !results["warm"].IsMatched() || !results["warm-ignored"].IsMatched() {
+		t.Errorf("Expected warm key to match")
+	}
+	if !results["cold"].IsMismatched() {
+		t.Errorf("Expected cold key to mismatch")
+	}
+
+	r := results["cold-ignored"]
+	if !r.IsIgnored() || r.Ignored[0].IgnoreReason != IgnoreMissReason {
+		t.Errorf("Expected cold key to be ignored")
+	}
+	if r.Experiment.Context["key"] != "cold" {
+		t.Errorf("Unexpected context: %v", r.Experiment.Context)
+	}
+}
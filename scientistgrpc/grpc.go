@@ -0,0 +1,100 @@
+// Package scientistgrpc runs experiments on gRPC calls, comparing the
+// responses of a current backend with a new one.
+package scientistgrpc
+
+import (
+	"context"
+	"encoding/json"
+	"scientist"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+// Interceptor is a unary client interceptor that also calls read-only
+// methods on a candidate backend. The control's response is returned to the
+// caller.
+//
+//	conn, err := grpc.NewClient(target, grpc.WithUnaryInterceptor(interceptor.Unary))
+type Interceptor struct {
+	// Name is the experiment name.
+	Name string
+
+	// Candidate is the connection to the new backend.
+	Candidate grpc.ClientConnInterface
+
+	// Methods lists the full names of read-only methods to call on both
+	// backends, like "/widgets.Widgets/GetWidget". Other methods only go to
+	// the control.
+	Methods []string
+
+	// Setup is called with each new experiment to set a publisher or other
+	// options.
+	Setup func(e *scientist.Experiment, method string)
+}
+
+func NewInterceptor(name string, candidate grpc.ClientConnInterface, methods ...string) *Interceptor {
+	return &Interceptor{Name: name, Candidate: candidate, Methods: methods}
+}
+
+// Unary is a grpc.UnaryClientInterceptor. Responses are compared with
+// protocmp, and published as protojson. Call options are only used for the
+// control, since options like grpc.Header write to the caller's variables.
+func (i *Interceptor) Unary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	msg, ok := reply.(proto.Message)
+	if !ok || !i.readOnly(method) {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+
+	e := scientist.New(i.Name)
+	e.Context["method"] = method
+
+	e.UseContext(func(ctx context.Context) (interface{}, error) {
+		if err := invoker(ctx, method, req, reply, cc, opts...); err != nil {
+			return nil, err
+		}
+		return msg, nil
+	})
+
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		candidate := msg.ProtoReflect().New().Interface()
+		if err := i.Candidate.Invoke(ctx, method, req, candidate); err != nil {
+			return nil, err
+		}
+		return candidate, nil
+	})
+
+	e.CompareOptions(protocmp.Transform())
+	e.Clean(clean)
+
+	if i.Setup != nil {
+		i.Setup(e, method)
+	}
+
+	_, err := e.RunContext(ctx)
+	return err
+}
+
+func (i *Interceptor) readOnly(method string) bool {
+	for _, m := range i.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func clean(v interface{}) (interface{}, error) {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return v, nil
+	}
+
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(data), nil
+}
@@ -0,0 +1,138 @@
+package scientistgrpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"scientist"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	getWidget    = "/widgets.Widgets/GetWidget"
+	deleteWidget = "/widgets.Widgets/DeleteWidget"
+)
+
+// serve answers every method with the request's value and a suffix.
+func serve(t *testing.T, suffix string, calls *int) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+		*calls++
+		req := &wrapperspb.StringValue{}
+		if err := stream.RecvMsg(req); err != nil {
+			return err
+		}
+		return stream.SendMsg(wrapperspb.String(req.Value + suffix))
+	}))
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return conn
+}
+
+// invoker sends calls to conn instead of the intercepted connection.
+func invoker(conn *grpc.ClientConn) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, _ *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return conn.Invoke(ctx, method, req, reply, opts...)
+	}
+}
+
+func TestInterceptor(t *testing.T) {
+	var controlCalls, candidateCalls int
+	controlConn := serve(t, "", &controlCalls)
+	candidateConn := serve(t, "!", &candidateCalls)
+
+	i := NewInterceptor("scientistgrpc", candidateConn, getWidget)
+
+	var results []scientist.Result
+	i.Setup = func(e *scientist.Experiment, method string) {
+		e.Publish(func(r scientist.Result) error {
+			results = append(results, r)
+			return nil
+		})
+	}
+
+	ctx := context.Background()
+	reply := &wrapperspb.StringValue{}
+	err := i.Unary(ctx, getWidget, wrapperspb.String("widget"), reply, nil, invoker(controlConn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Value != "widget" {
+		t.Errorf("Unexpected control reply: %q", reply.Value)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	r := results[0]
+	if !r.IsMismatched() || !strings.Contains(r.Diff(), `"widget!"`) {
+		t.Errorf("Expected mismatch, got:\n%s", r.Diff())
+	}
+	if v, _ := r.Candidates[0].CleanedValue(); string(v.(json.RawMessage)) != `"widget!"` {
+		t.Errorf("Unexpected cleaned candidate: %s", v)
+	}
+
+	err = i.Unary(ctx, deleteWidget, wrapperspb.String("widget"), &wrapperspb.StringValue{}, nil, invoker(controlConn))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if controlCalls != 2 || candidateCalls != 1 {
+		t.Errorf("Unexpected calls: control %d, candidate %d", controlCalls, candidateCalls)
+	}
+}
+
+func TestInterceptorMatch(t *testing.T) {
+	var controlCalls, candidateCalls int
+	controlConn := serve(t, "", &controlCalls)
+	candidateConn := serve(t, "", &candidateCalls)
+
+	i := NewInterceptor("scientistgrpc-match", candidateConn, getWidget)
+
+	var result scientist.Result
+	i.Setup = func(e *scientist.Experiment, method string) {
+		e.Publish(func(r scientist.Result) error {
+			result = r
+			return nil
+		})
+	}
+
+	conn, err := grpc.NewClient("passthrough:///unused",
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, _ grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return i.Unary(ctx, method, req, reply, cc, invoker(controlConn), opts...)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reply := &wrapperspb.StringValue{}
+	if err := conn.Invoke(context.Background(), getWidget, wrapperspb.String("widget"), reply); err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.IsMatched() {
+		t.Errorf("Unexpected mismatch:\n%s", result.Diff())
+	}
+}
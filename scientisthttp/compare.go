@@ -0,0 +1,130 @@
+package scientisthttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+)
+
+// CompareOptions chooses which parts of a response are compared.
+type CompareOptions struct {
+	// Headers lists the response headers to compare. Other headers are
+	// ignored, since many, like Date, are expected to differ.
+	Headers []string
+
+	// JSON compares bodies as JSON, ignoring key order and whitespace. Bodies
+	// that aren't valid JSON are compared as bytes.
+	JSON bool
+}
+
+// NewResponse buffers the body of res, replacing it with a copy so res can
+// still be read.
+func NewResponse(res *http.Response) (*Response, error) {
+	var body []byte
+	if res.Body != nil {
+		var err error
+		body, err = io.ReadAll(res.Body)
+		res.Body.Close()
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &Response{
+		StatusCode: res.StatusCode,
+		Header:     res.Header.Clone(),
+		Body:       append([]byte(nil), body...),
+	}, nil
+}
+
+// Clean returns a copy of the response with only the compared headers, and a
+// normalized JSON body.
+func (o CompareOptions) Clean(r *Response) *Response {
+	cleaned := &Response{StatusCode: r.StatusCode, Header: make(http.Header), Body: r.Body}
+	for _, key := range o.Headers {
+		if values := r.Header.Values(key); len(values) > 0 {
+			cleaned.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+		}
+	}
+
+	if o.JSON {
+		var v interface{}
+		if err := json.Unmarshal(r.Body, &v); err == nil {
+			// Maps are encoded with sorted keys.
+			if body, err := json.Marshal(v); err == nil {
+				cleaned.Body = body
+			}
+		}
+	}
+
+	return cleaned
+}
+
+// Compare returns a comparator for responses. Values can be a *Response, an
+// *http.Response, or an *httptest.ResponseRecorder.
+//
+//	e.Compare(scientisthttp.CompareOptions{Headers: []string{"Content-Type"}, JSON: true}.Compare())
+func (o CompareOptions) Compare() func(control, candidate interface{}) (bool, error) {
+	return func(control, candidate interface{}) (bool, error) {
+		a, err := o.response(control)
+		if err != nil {
+			return false, err
+		}
+
+		b, err := o.response(candidate)
+		if err != nil {
+			return false, err
+		}
+
+		return reflect.DeepEqual(a, b), nil
+	}
+}
+
+// Cleaner returns a cleaner that publishes responses as a cleaned *Response.
+// Other values are published as is.
+func (o CompareOptions) Cleaner() func(v interface{}) (interface{}, error) {
+	return func(v interface{}) (interface{}, error) {
+		switch v.(type) {
+		case *Response, *http.Response, *httptest.ResponseRecorder:
+			return o.response(v)
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (o CompareOptions) response(v interface{}) (*Response, error) {
+	switch r := v.(type) {
+	case *Response:
+		return o.Clean(r), nil
+	case *http.Response:
+		res, err := NewResponse(r)
+		if err != nil {
+			return nil, err
+		}
+		return o.Clean(res), nil
+	case *httptest.ResponseRecorder:
+		res, err := NewResponse(r.Result())
+		if err != nil {
+			return nil, err
+		}
+		return o.Clean(res), nil
+	default:
+		return nil, fmt.Errorf("[scientist] %T is not a response", v)
+	}
+}
+
+// MarshalJSON encodes the body as a string, so published responses are
+// readable.
+func (r *Response) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		StatusCode int         `json:"status_code"`
+		Header     http.Header `json:"header,omitempty"`
+		Body       string      `json:"body"`
+	}{r.StatusCode, r.Header, string(r.Body)})
+}
@@ -0,0 +1,112 @@
+package scientisthttp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	opts := CompareOptions{Headers: []string{"content-type"}, JSON: true}
+	compare := opts.Compare()
+
+	control := &Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/json"}, "Date": {"today"}},
+		Body:       []byte(`{"id": 1, "name": "widget"}`),
+	}
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.Header().Set("Date", "tomorrow")
+	rec.WriteString(`{"name":"widget","id":1}`)
+
+	res := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"name":"widget","id":1}`)),
+	}
+
+	for name, candidate := range map[string]interface{}{"recorder": rec, "response": res} {
+		equal, err := compare(control, candidate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !equal {
+			t.Errorf("Expected %s to match", name)
+		}
+	}
+
+	// The response body can still be read.
+	if body, _ := io.ReadAll(res.Body); len(body) == 0 {
+		t.Errorf("Expected buffered response body")
+	}
+
+	for name, candidate := range map[string]*Response{
+		"status": {StatusCode: 500, Header: control.Header, Body: control.Body},
+		"header": {StatusCode: 200, Header: http.Header{"Content-Type": {"text/plain"}}, Body: control.Body},
+		"body":   {StatusCode: 200, Header: control.Header, Body: []byte(`{"id":2,"name":"widget"}`)},
+	} {
+		equal, err := compare(control, candidate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if equal {
+			t.Errorf("Expected %s to mismatch", name)
+		}
+	}
+
+	if _, err := compare(control, "nope"); err == nil {
+		t.Errorf("Expected error comparing a string")
+	}
+}
+
+func TestCompareBytes(t *testing.T) {
+	compare := CompareOptions{}.Compare()
+
+	equal, err := compare(&Response{StatusCode: 200, Body: []byte(`{"a":1,"b":2}`)}, &Response{StatusCode: 200, Body: []byte(`{"b":2,"a":1}`)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if equal {
+		t.Errorf("Expected bodies to be compared as bytes")
+	}
+
+	compare = CompareOptions{JSON: true}.Compare()
+	equal, err = compare(&Response{StatusCode: 404, Body: []byte("not found")}, &Response{StatusCode: 404, Body: []byte("not found")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !equal {
+		t.Errorf("Expected invalid JSON bodies to be compared as bytes")
+	}
+}
+
+func TestCleaner(t *testing.T) {
+	clean := CompareOptions{Headers: []string{"Content-Type"}, JSON: true}.Cleaner()
+
+	v, err := clean(&Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": {"application/json"}, "Date": {"today"}},
+		Body:       []byte(`{ "id": 1 }`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"status_code":200,"header":{"Content-Type":["application/json"]},"body":"{\"id\":1}"}`
+	if string(data) != expected {
+		t.Errorf("Unexpected cleaned response: %s", data)
+	}
+
+	if v, _ := clean(1); v != 1 {
+		t.Errorf("Unexpected cleaned value: %v", v)
+	}
+}
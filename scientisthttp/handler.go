@@ -0,0 +1,141 @@
+package scientisthttp
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"scientist"
+)
+
+// Handler serves requests with the control handler, and shadows safe
+// requests, GET and HEAD without a body, to the candidate handler. The
+// candidate's response is recorded and compared, but never sent to the
+// client. When the experiment runs, both responses are buffered in memory.
+// Otherwise, the control's response is written straight to the client.
+type Handler struct {
+	// Name is the experiment name.
+	Name string
+
+	Control   http.Handler
+	Candidate http.Handler
+
+	// CompareOptions choose the headers to compare, and whether bodies are
+	// compared as JSON.
+	CompareOptions
+
+	// Normalize is called with both responses before they are compared, to
+	// remove expected differences like timestamps or request IDs.
+	Normalize func(r *Response)
+
+	// Setup is called with each new experiment to set a publisher, comparator,
+	// or other options.
+	Setup func(e *scientist.Experiment, req *http.Request)
+
+	// WaitForCandidate runs the candidate before ServeHTTP returns, which
+	// adds its latency to every shadowed request. By default, the candidate
+	// runs in the background with EnableAsync once the control responds.
+	WaitForCandidate bool
+}
+
+func NewHandler(name string, control, candidate http.Handler) *Handler {
+	return &Handler{Name: name, Control: control, Candidate: candidate}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !safe(req) {
+		h.Control.ServeHTTP(w, req)
+		return
+	}
+
+	e := scientist.New(h.Name)
+	e.Context["method"] = req.Method
+	e.Context["path"] = req.URL.Path
+
+	if !h.WaitForCandidate {
+		e.EnableAsync()
+	}
+
+	e.UseContext(func(ctx context.Context) (interface{}, error) {
+		if !scientist.Observed(ctx) {
+			h.Control.ServeHTTP(w, req)
+			return nil, nil
+		}
+
+		tw := &teeWriter{ResponseWriter: w}
+		h.Control.ServeHTTP(tw, req)
+		return h.clean(tw.response()), nil
+	})
+
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		rec := httptest.NewRecorder()
+		h.Candidate.ServeHTTP(rec, req.Clone(ctx))
+
+		res, err := NewResponse(rec.Result())
+		if err != nil {
+			return nil, err
+		}
+		return h.clean(res), nil
+	})
+
+	if h.Setup != nil {
+		h.Setup(e, req)
+	}
+
+	e.RunContext(req.Context())
+}
+
+func (h *Handler) clean(r *Response) *Response {
+	r = h.Clean(r)
+	if h.Normalize != nil {
+		h.Normalize(r)
+	}
+	return r
+}
+
+// teeWriter writes the control's response to the client, keeping a copy to
+// compare.
+type teeWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *teeWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *teeWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *teeWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the client's ResponseWriter.
+func (w *teeWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *teeWriter) response() *Response {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	return &Response{
+		StatusCode: status,
+		Header:     w.Header().Clone(),
+		Body:       append([]byte(nil), w.body.Bytes()...),
+	}
+}
@@ -0,0 +1,145 @@
+package scientisthttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"scientist"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	// candidates run in the background, so their calls and results are
+	// counted under a lock.
+	var mu sync.Mutex
+	var candidateCalls int
+	control := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "1")
+		fmt.Fprintf(w, `{"path":%q, "version":1}`, req.URL.Path)
+	})
+	candidate := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		candidateCalls++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "2")
+		if req.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprintf(w, `{"version":2,"path":%q}`, req.URL.Path)
+	})
+
+	h := NewHandler("scientisthttp-handler", control, candidate)
+	h.Headers = []string{"Content-Type"}
+	h.JSON = true
+	h.Normalize = func(r *Response) {
+		r.Body = []byte(strings.Replace(string(r.Body), `"version":2`, `"version":1`, 1))
+	}
+
+	results := make(map[string]scientist.Result)
+	h.Setup = func(e *scientist.Experiment, req *http.Request) {
+		e.Publish(func(r scientist.Result) error {
+			mu.Lock()
+			results[req.URL.Path] = r
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	for _, path := range []string{"/widgets", "/missing"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		if w.Code != 200 || w.Header().Get("X-Request-Id") != "1" {
+			t.Errorf("Unexpected control response: %d %v", w.Code, w.Header())
+		}
+		if expected := fmt.Sprintf(`{"path":%q, "version":1}`, path); w.Body.String() != expected {
+			t.Errorf("Unexpected control body: %s", w.Body.String())
+		}
+	}
+
+	if err := scientist.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if candidateCalls != 2 || len(results) != 2 {
+		t.Fatalf("Unexpected candidate calls %d and results %d", candidateCalls, len(results))
+	}
+	if r := results["/widgets"]; !r.IsMatched() {
+		t.Errorf("Unexpected mismatch:\n%s", r.Diff())
+	}
+	if r := results["/missing"]; !r.IsMismatched() || !strings.Contains(r.Diff(), "404") {
+		t.Errorf("Expected status mismatch, got:\n%s", r.Diff())
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("POST", "/widgets", strings.NewReader("{}")))
+	if candidateCalls != 2 || w.Code != 200 {
+		t.Errorf("Unexpected shadowed POST: %d candidate calls, %d status", candidateCalls, w.Code)
+	}
+}
+
+func TestHandlerWaitForCandidate(t *testing.T) {
+	control := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, "control")
+	})
+	candidate := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, "candidate")
+	})
+
+	h := NewHandler("scientisthttp-handler-wait", control, candidate)
+	h.WaitForCandidate = true
+
+	var results []scientist.Result
+	h.Setup = func(e *scientist.Experiment, req *http.Request) {
+		e.Publish(func(r scientist.Result) error {
+			results = append(results, r)
+			return nil
+		})
+	}
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if len(results) != 1 || !results[0].IsMismatched() {
+		t.Errorf("Expected the candidate to finish before ServeHTTP returned: %+v", results)
+	}
+}
+
+// streamRecorder counts writes, to check that the control's response goes
+// straight to the client.
+type streamRecorder struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (w *streamRecorder) Write(p []byte) (int, error) {
+	w.writes++
+	return w.ResponseRecorder.Write(p)
+}
+
+func TestHandlerSkipped(t *testing.T) {
+	var candidateCalls int
+	control := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if _, ok := w.(*streamRecorder); !ok {
+			t.Errorf("Expected the control to write to the client directly, got %T", w)
+		}
+		fmt.Fprint(w, "control")
+	})
+	candidate := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		candidateCalls++
+	})
+
+	h := NewHandler("scientisthttp-handler-skipped", control, candidate)
+	h.Setup = func(e *scientist.Experiment, req *http.Request) {
+		e.RunPercent(0)
+	}
+
+	w := &streamRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Body.String() != "control" || w.writes != 1 || candidateCalls != 0 {
+		t.Errorf("Unexpected skipped response: %q, %d writes, %d candidate calls", w.Body.String(), w.writes, candidateCalls)
+	}
+}
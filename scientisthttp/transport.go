@@ -0,0 +1,104 @@
+// Package scientisthttp runs experiments on HTTP traffic, comparing the
+// responses of a current backend with a new one.
+package scientisthttp
+
+import (
+	"context"
+	"net/http"
+	"scientist"
+)
+
+// Response is a buffered HTTP response. It's the value of each behavior in
+// an HTTP experiment.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Transport is an http.RoundTripper that sends safe requests, GET and HEAD
+// without a body, to both the control and candidate round trippers. The
+// control's response is returned to the caller. Other requests only go to the
+// control.
+type Transport struct {
+	// Name is the experiment name.
+	Name string
+
+	Control   http.RoundTripper
+	Candidate http.RoundTripper
+
+	// CompareOptions choose the headers to compare, and whether bodies are
+	// compared as JSON.
+	CompareOptions
+
+	// Normalize is called with both responses before they are compared, to
+	// remove expected differences like timestamps or request IDs. It gets a
+	// copy of the control response, so changes don't affect the caller.
+	Normalize func(r *Response)
+
+	// Setup is called with each new experiment to set a publisher, comparator,
+	// or other options.
+	Setup func(e *scientist.Experiment, req *http.Request)
+}
+
+func NewTransport(name string, control, candidate http.RoundTripper) *Transport {
+	return &Transport{Name: name, Control: control, Candidate: candidate}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !safe(req) {
+		return t.Control.RoundTrip(req)
+	}
+
+	var res *http.Response
+	e := scientist.New(t.Name)
+	e.Context["method"] = req.Method
+	e.Context["path"] = req.URL.Path
+
+	e.UseContext(func(ctx context.Context) (interface{}, error) {
+		var err error
+		if res, err = t.Control.RoundTrip(req); err != nil {
+			return nil, err
+		}
+		return t.buffer(res)
+	})
+
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		res, err := t.Candidate.RoundTrip(req.Clone(ctx))
+		if err != nil {
+			return nil, err
+		}
+		return t.buffer(res)
+	})
+
+	if t.Setup != nil {
+		t.Setup(e, req)
+	}
+
+	if _, err := e.RunContext(req.Context()); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func safe(req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody
+}
+
+// buffer reads the body of res, replacing it with a buffered copy so the
+// control's response can still be read by the caller.
+func (t *Transport) buffer(res *http.Response) (*Response, error) {
+	r, err := NewResponse(res)
+	if err != nil {
+		return nil, err
+	}
+
+	r = t.Clean(r)
+	if t.Normalize != nil {
+		t.Normalize(r)
+	}
+	return r, nil
+}
@@ -0,0 +1,148 @@
+package scientisthttp
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func backend(calls *int, status int, body string, header http.Header) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		*calls++
+		h := make(http.Header)
+		for key, values := range header {
+			h[key] = values
+		}
+		return &http.Response{
+			StatusCode: status,
+			Header:     h,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestTransport(t *testing.T) {
+	var controlCalls, candidateCalls int
+	tr := NewTransport("scientisthttp-transport",
+		backend(&controlCalls, 200, `{"id":1}`, http.Header{"Content-Type": {"application/json"}, "Date": {"today"}}),
+		backend(&candidateCalls, 200, `{"id":1}`, http.Header{"Content-Type": {"application/json"}, "Date": {"tomorrow"}}),
+	)
+	tr.Headers = []string{"content-type"}
+
+	var results []scientist.Result
+	tr.Setup = func(e *scientist.Experiment, req *http.Request) {
+		e.Publish(func(r scientist.Result) error {
+			results = append(results, r)
+			return nil
+		})
+	}
+
+	client := &http.Client{Transport: tr}
+	res, err := client.Get("http://example.com/widgets/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != `{"id":1}` || res.Header.Get("Date") != "today" {
+		t.Errorf("Unexpected control response: %s %v", body, res.Header)
+	}
+
+	if controlCalls != 1 || candidateCalls != 1 {
+		t.Errorf("Unexpected calls: control %d, candidate %d", controlCalls, candidateCalls)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+	if r := results[0]; !r.IsMatched() {
+		t.Errorf("Unexpected mismatch:\n%s", r.Diff())
+	}
+	if path := results[0].Experiment.Context["path"]; path != "/widgets/1" {
+		t.Errorf("Unexpected path context: %q", path)
+	}
+}
+
+func TestTransportMismatch(t *testing.T) {
+	var controlCalls, candidateCalls int
+	tr := NewTransport("scientisthttp-transport-mismatch",
+		backend(&controlCalls, 200, `{"id":1,"at":"today"}`, nil),
+		backend(&candidateCalls, 404, `{"id":1,"at":"tomorrow"}`, nil),
+	)
+
+	var result scientist.Result
+	tr.Setup = func(e *scientist.Experiment, req *http.Request) {
+		e.Publish(func(r scientist.Result) error {
+			result = r
+			return nil
+		})
+	}
+
+	res, err := tr.RoundTrip(newRequest(t, "GET", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.StatusCode != 200 {
+		t.Errorf("Unexpected control status: %d", res.StatusCode)
+	}
+
+	if !result.IsMismatched() || !strings.Contains(result.Diff(), "StatusCode") {
+		t.Errorf("Expected status mismatch, got:\n%s", result.Diff())
+	}
+
+	tr.Normalize = func(r *Response) {
+		r.StatusCode = 200
+		r.Body = bytes.Replace(r.Body, []byte("tomorrow"), []byte("today"), 1)
+	}
+	res, err = tr.RoundTrip(newRequest(t, "GET", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsMatched() {
+		t.Errorf("Unexpected mismatch after normalizing:\n%s", result.Diff())
+	}
+
+	// The caller gets the control body before it was normalized.
+	body, _ := io.ReadAll(res.Body)
+	if string(body) != `{"id":1,"at":"today"}` {
+		t.Errorf("Unexpected control body: %s", body)
+	}
+}
+
+func TestTransportUnsafe(t *testing.T) {
+	var controlCalls, candidateCalls int
+	tr := NewTransport("scientisthttp-transport-unsafe",
+		backend(&controlCalls, 201, "", nil),
+		backend(&candidateCalls, 201, "", nil),
+	)
+
+	if _, err := tr.RoundTrip(newRequest(t, "POST", nil)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.RoundTrip(newRequest(t, "GET", strings.NewReader("body"))); err != nil {
+		t.Fatal(err)
+	}
+
+	if controlCalls != 2 || candidateCalls != 0 {
+		t.Errorf("Unexpected calls: control %d, candidate %d", controlCalls, candidateCalls)
+	}
+}
+
+func newRequest(t *testing.T, method string, body io.Reader) *http.Request {
+	req, err := http.NewRequest(method, "http://example.com/widgets", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
@@ -0,0 +1,137 @@
+// Package scientistsql runs experiments on SQL queries, comparing the rows
+// returned by two databases, or by two different queries.
+package scientistsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"scientist"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+// Queryer runs a query. It's implemented by *sql.DB, *sql.Tx, and *sql.Conn.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Query is a query to run on a database.
+type Query struct {
+	DB   Queryer
+	SQL  string
+	Args []interface{}
+}
+
+// Rows are the materialized results of a query. Byte slices are converted to
+// strings, so text columns compare and print the same with any driver.
+type Rows struct {
+	Columns []string
+	Values  [][]interface{}
+}
+
+// New returns an experiment that runs the control and candidate queries, and
+// compares their rows in any order. Each behavior returns *Rows. Set your own
+// CompareOptions to compare rows in order, like for queries with ORDER BY.
+//
+//	e := scientistsql.New("widgets-by-owner",
+//	  scientistsql.Query{DB: mysql, SQL: "SELECT id, name FROM widgets WHERE owner_id = ?", Args: []interface{}{id}},
+//	  scientistsql.Query{DB: postgres, SQL: "SELECT id, name FROM widgets WHERE owner_id = $1", Args: []interface{}{id}},
+//	)
+func New(name string, control, candidate Query) *scientist.Experiment {
+	e := scientist.New(name)
+	e.UseContext(control.Run)
+	e.TryContext(candidate.Run)
+	e.CompareOptions(Unordered())
+	return e
+}
+
+// Run runs the query and materializes its rows. It can be used as a behavior
+// with Experiment.UseContext or Experiment.TryContext.
+func (q Query) Run(ctx context.Context) (interface{}, error) {
+	return Materialize(ctx, q.DB, q.SQL, q.Args...)
+}
+
+// Materialize runs a query and reads all of its rows.
+func Materialize(ctx context.Context, db Queryer, query string, args ...interface{}) (*Rows, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Rows{Columns: columns, Values: [][]interface{}{}}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		r.Values = append(r.Values, values)
+	}
+
+	return r, rows.Err()
+}
+
+// Unordered compares the rows of *Rows values in any order. Columns are still
+// compared in order.
+func Unordered() cmp.Option {
+	return cmpopts.SortSlices(func(a, b []interface{}) bool {
+		return rowKey(a) < rowKey(b)
+	})
+}
+
+// rowKey sorts rows by their values, including the types so that 1 and "1"
+// don't sort as equal.
+func rowKey(row []interface{}) string {
+	return fmt.Sprintf("%#v", row)
+}
+
+type txKey struct{}
+
+// Rollback returns an Experiment.Sandbox function that runs each candidate in
+// a transaction on db, which is always rolled back. Candidates get the
+// transaction with Tx.
+//
+//	e.Sandbox(scientistsql.Rollback(db))
+//	e.TryContext(func(ctx context.Context) (interface{}, error) {
+//	  return createWidget(ctx, scientistsql.Tx(ctx), name)
+//	})
+func Rollback(db *sql.DB) func(ctx context.Context, run func(ctx context.Context) error) error {
+	return func(ctx context.Context, run func(ctx context.Context) error) error {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		run(context.WithValue(ctx, txKey{}, tx))
+
+		// The transaction is already rolled back if ctx was canceled.
+		if err := tx.Rollback(); err != nil && err != sql.ErrTxDone {
+			return err
+		}
+		return nil
+	}
+}
+
+// Tx returns the transaction of a candidate run with Rollback, or nil.
+func Tx(ctx context.Context) *sql.Tx {
+	tx, _ := ctx.Value(txKey{}).(*sql.Tx)
+	return tx
+}
@@ -0,0 +1,258 @@
+package scientistsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+// fakeDriver answers queries from canned tables, keyed by DSN and query.
+type fakeDriver map[string]map[string]fakeRows
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+type fakeConn struct {
+	tables map[string]fakeRows
+	txs    *txCounts
+}
+
+// fakeConnector opens connections that count their transactions, so each test
+// starts counting from zero.
+type fakeConnector struct {
+	driver fakeDriver
+	dsn    string
+	txs    *txCounts
+}
+
+type fakeTx struct {
+	conn *fakeConn
+}
+
+type txCounts struct {
+	committed  int
+	rolledBack int
+}
+
+type fakeCursor struct {
+	rows fakeRows
+	i    int
+}
+
+var fake = fakeDriver{
+	"control": {
+		"SELECT id, name FROM widgets": {
+			columns: []string{"id", "name"},
+			values:  [][]driver.Value{{int64(1), []byte("one")}, {int64(2), []byte("two")}},
+		},
+	},
+	"candidate": {
+		"SELECT id, name FROM widgets": {
+			columns: []string{"id", "name"},
+			values:  [][]driver.Value{{int64(2), []byte("two")}, {int64(1), []byte("one")}},
+		},
+		"SELECT id, name FROM new_widgets": {
+			columns: []string{"id", "name"},
+			values:  [][]driver.Value{{int64(1), []byte("one")}, {int64(2), []byte("deux")}},
+		},
+	},
+}
+
+func init() {
+	sql.Register("scientistsql-fake", fake)
+}
+
+func (d fakeDriver) Open(dsn string) (driver.Conn, error) {
+	return &fakeConn{tables: d[dsn], txs: &txCounts{}}, nil
+}
+
+func (c fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeConn{tables: c.driver[c.dsn], txs: c.txs}, nil
+}
+
+func (c fakeConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, ok := c.tables[query]
+	if !ok {
+		return nil, errors.New("no such table")
+	}
+	return &fakeCursor{rows: rows}, nil
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{c}, nil
+}
+
+func (tx fakeTx) Commit() error {
+	tx.conn.txs.committed++
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.conn.txs.rolledBack++
+	return nil
+}
+
+func (c *fakeCursor) Columns() []string {
+	return c.rows.columns
+}
+
+func (c *fakeCursor) Close() error {
+	return nil
+}
+
+func (c *fakeCursor) Next(dest []driver.Value) error {
+	if c.i >= len(c.rows.values) {
+		return io.EOF
+	}
+	copy(dest, c.rows.values[c.i])
+	c.i++
+	return nil
+}
+
+func open(t *testing.T, dsn string) *sql.DB {
+	db, err := sql.Open("scientistsql-fake", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+	return db
+}
+
+func TestMaterialize(t *testing.T) {
+	rows, err := Materialize(context.Background(), open(t, "control"), "SELECT id, name FROM widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows.Columns) != 2 || rows.Columns[1] != "name" {
+		t.Errorf("Unexpected columns: %v", rows.Columns)
+	}
+	if len(rows.Values) != 2 || rows.Values[0][0] != int64(1) || rows.Values[0][1] != "one" {
+		t.Errorf("Unexpected values: %v", rows.Values)
+	}
+}
+
+func TestNew(t *testing.T) {
+	control := open(t, "control")
+	candidate := open(t, "candidate")
+
+	var result scientist.Result
+	e := New("scientistsql",
+		Query{DB: control, SQL: "SELECT id, name FROM widgets"},
+		Query{DB: candidate, SQL: "SELECT id, name FROM widgets"},
+	)
+	e.Publish(func(r scientist.Result) error {
+		result = r
+		return nil
+	})
+
+	v, err := e.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows := v.(*Rows); rows.Values[0][1] != "one" {
+		t.Errorf("Unexpected control rows: %v", rows.Values)
+	}
+	if !result.IsMatched() {
+		t.Errorf("Expected rows in any order to match:\n%s", result.Diff())
+	}
+
+	e = New("scientistsql",
+		Query{DB: control, SQL: "SELECT id, name FROM widgets"},
+		Query{DB: candidate, SQL: "SELECT id, name FROM new_widgets"},
+	)
+	e.Publish(func(r scientist.Result) error {
+		result = r
+		return nil
+	})
+	e.Run()
+
+	if !result.IsMismatched() || !strings.Contains(result.Diff(), `"deux"`) {
+		t.Errorf("Expected mismatch, got:\n%s", result.Diff())
+	}
+}
+
+func TestNewOrdered(t *testing.T) {
+	var result scientist.Result
+	e := New("scientistsql-ordered",
+		Query{DB: open(t, "control"), SQL: "SELECT id, name FROM widgets"},
+		Query{DB: open(t, "candidate"), SQL: "SELECT id, name FROM widgets"},
+	)
+	e.CompareOptions()
+	e.Publish(func(r scientist.Result) error {
+		result = r
+		return nil
+	})
+	e.Run()
+
+	if !result.IsMismatched() {
+		t.Errorf("Expected rows in a different order to mismatch")
+	}
+}
+
+func TestRollback(t *testing.T) {
+	txs := &txCounts{}
+	db := sql.OpenDB(fakeConnector{driver: fake, dsn: "candidate", txs: txs})
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	e := scientist.New("scientistsql-rollback")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		tx := Tx(ctx)
+		if tx == nil {
+			return nil, errors.New("no transaction")
+		}
+
+		if _, err := tx.QueryContext(ctx, "SELECT id, name FROM widgets"); err != nil {
+			return nil, err
+		}
+		return 1, nil
+	})
+	e.Sandbox(Rollback(db))
+
+	var result scientist.Result
+	e.Publish(func(r scientist.Result) error {
+		result = r
+		return nil
+	})
+	e.ReportErrors(func(errs ...scientist.ResultError) {
+		t.Errorf("Unexpected errors: %v", errs)
+	})
+	e.Run()
+
+	if !result.IsMatched() {
+		t.Errorf("Unexpected candidate error: %v", result.Candidates[0].Err)
+	}
+	if txs.committed != 0 || txs.rolledBack != 1 {
+		t.Errorf("Expected rollback, got %d commits and %d rollbacks", txs.committed, txs.rolledBack)
+	}
+
+	if Tx(context.Background()) != nil {
+		t.Errorf("Unexpected transaction")
+	}
+}
@@ -0,0 +1,97 @@
+package scientisttest
+
+import (
+	"scientist"
+	"sort"
+	"sync"
+	"testing"
+)
+
+type behaviorStats struct {
+	runs    int
+	runtime float64
+	allocs  uint64
+}
+
+// Benchmark runs the experiment b.N times, and reports the average runtime
+// and allocations of each behavior side by side, as "<behavior>-ns/op" and
+// "<behavior>-allocs/op" metrics. Like Run, the benchmark fails if any
+// candidate mismatches, or if any ResultError is reported.
+//
+// Benchmark measures allocations, and sets the experiment's ReportErrors and
+// AfterRun callbacks, so pass it an experiment that hasn't run yet.
+// Allocations come from MeasureAllocations, which counts every allocation
+// during a behavior's call, even small ones. They're counted for the whole
+// process, so they're only accurate when behaviors run one at a time.
+func Benchmark(b *testing.B, e *scientist.Experiment) {
+	b.Helper()
+
+	var mu sync.Mutex
+	stats := make(map[string]*behaviorStats)
+	var mismatch *scientist.Result
+	var reported []scientist.ResultError
+
+	e.MeasureAllocations()
+	e.AfterRun(func(r scientist.Result) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, o := range r.Observations {
+			s, ok := stats[o.Name]
+			if !ok {
+				s = &behaviorStats{}
+				stats[o.Name] = s
+			}
+			s.runs++
+			s.runtime += float64(o.Runtime.Nanoseconds())
+			s.allocs += o.Allocs
+		}
+
+		if mismatch == nil && r.IsMismatched() {
+			mismatch = &r
+		}
+		return nil
+	})
+
+	e.ReportErrors(func(errs ...scientist.ResultError) {
+		mu.Lock()
+		reported = append(reported, errs...)
+		mu.Unlock()
+	})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.Run()
+	}
+	b.StopTimer()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		s := stats[name]
+		b.ReportMetric(s.runtime/float64(s.runs), name+"-ns/op")
+		b.ReportMetric(float64(s.allocs)/float64(s.runs), name+"-allocs/op")
+	}
+
+	if len(stats) == 0 && len(reported) == 0 {
+		b.Errorf("experiment %q didn't run its candidates", e.Name)
+	}
+
+	if mismatch != nil {
+		b.Errorf("experiment %q mismatched:\n%s", e.Name, mismatch.Diff())
+	}
+
+	// Only the first error is shown, since the same one usually repeats for
+	// every run.
+	if len(reported) > 0 {
+		err := reported[0]
+		b.Errorf("experiment %q %s error: %v (%d errors reported)", err.Experiment, err.Operation, err.Err, len(reported))
+	}
+}
@@ -0,0 +1,69 @@
+package scientisttest
+
+import (
+	"testing"
+)
+
+func TestBenchmark(t *testing.T) {
+	res := testing.Benchmark(func(b *testing.B) {
+		e := experiment("scientisttest-benchmark", 1)
+		e.Behavior("alloc", func() (interface{}, error) {
+			return len(make([]byte, 1<<20)), nil
+		})
+		e.Compare(func(control, candidate interface{}) (bool, error) {
+			return true, nil
+		})
+		Benchmark(b, e)
+	})
+
+	if res.N == 0 {
+		t.Fatalf("Expected the benchmark to pass")
+	}
+
+	for _, name := range []string{"control", "candidate", "alloc"} {
+		if _, ok := res.Extra[name+"-ns/op"]; !ok {
+			t.Errorf("Expected a %s-ns/op metric: %v", name, res.Extra)
+		}
+	}
+
+	if res.Extra["alloc-allocs/op"] < 1 {
+		t.Errorf("Expected allocations for the alloc behavior: %v", res.Extra)
+	}
+}
+
+var smallSink [10]*[16]byte
+
+func TestBenchmarkSmallAllocations(t *testing.T) {
+	res := testing.Benchmark(func(b *testing.B) {
+		e := experiment("scientisttest-benchmark-small", 1)
+		e.Behavior("small", func() (interface{}, error) {
+			for i := range smallSink {
+				smallSink[i] = new([16]byte)
+			}
+			return 1, nil
+		})
+		Benchmark(b, e)
+	})
+
+	if res.N == 0 {
+		t.Fatalf("Expected the benchmark to pass")
+	}
+
+	if allocs := res.Extra["small-allocs/op"]; allocs != 10 {
+		t.Errorf("Expected 10 allocs/op for the small behavior, got %v", allocs)
+	}
+
+	if allocs := res.Extra["control-allocs/op"]; allocs != 0 {
+		t.Errorf("Expected no allocs/op for the control, got %v", allocs)
+	}
+}
+
+func TestBenchmarkMismatch(t *testing.T) {
+	res := testing.Benchmark(func(b *testing.B) {
+		Benchmark(b, experiment("scientisttest-benchmark-mismatch", 2))
+	})
+
+	if res.N != 0 {
+		t.Errorf("Expected the benchmark to fail")
+	}
+}
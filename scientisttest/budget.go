@@ -0,0 +1,77 @@
+package scientisttest
+
+import (
+	"errors"
+	"fmt"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+// Budget fails a test run when too many results mismatch. Add its Publish
+// method to every experiment, then check it at the end of the run:
+//
+//	var budget = scientisttest.NewBudget(0.001)
+//
+//	func TestMain(m *testing.M) {
+//	  scientist.SetDefaults(func(e *scientist.Experiment) {
+//	    e.AfterRun(budget.Publish)
+//	  })
+//
+//	  code := m.Run()
+//	  if err := budget.Err(); err != nil {
+//	    fmt.Println(err)
+//	    code = 1
+//	  }
+//	  os.Exit(code)
+//	}
+//
+// AfterRun sees every result, while a publisher skips matched results sampled
+// out by SampleMatched.
+type Budget struct {
+	// MaxMismatchRate is the highest mismatch rate allowed for each
+	// experiment, from 0 to 1.
+	MaxMismatchRate float64
+
+	agg *scientist.Aggregator
+}
+
+func NewBudget(maxMismatchRate float64) *Budget {
+	return &Budget{MaxMismatchRate: maxMismatchRate, agg: scientist.NewAggregator(1)}
+}
+
+func (b *Budget) Publish(r scientist.Result) error {
+	return b.agg.Publish(r)
+}
+
+// Err describes each experiment over budget, with its most common mismatch.
+func (b *Budget) Err() error {
+	var failures []string
+	for _, s := range b.agg.Summary() {
+		if s.MismatchRate <= b.MaxMismatchRate {
+			continue
+		}
+
+		msg := fmt.Sprintf("experiment %q mismatched %d of %d runs (%.2f%%), over the %.2f%% budget",
+			s.Experiment, s.Mismatched, s.Runs, s.MismatchRate*100, b.MaxMismatchRate*100)
+		if len(s.Mismatches) > 0 {
+			m := s.Mismatches[0]
+			msg += fmt.Sprintf("\n%s mismatched %d times:\n%s", m.Candidate, m.Count, m.Diff)
+		}
+		failures = append(failures, msg)
+	}
+
+	if len(failures) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(failures, "\n"))
+}
+
+// Check fails the test if any experiment is over budget.
+func (b *Budget) Check(t testing.TB) {
+	t.Helper()
+
+	if err := b.Err(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,42 @@
+package scientisttest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBudget(t *testing.T) {
+	b := NewBudget(0.1)
+	for i := 0; i < 20; i++ {
+		candidate := 1
+		if i < 2 {
+			candidate = 2
+		}
+
+		e := experiment("scientisttest-budget", candidate)
+		e.AfterRun(b.Publish)
+		e.Run()
+	}
+
+	if err := b.Err(); err != nil {
+		t.Errorf("Unexpected error at 10%% mismatched: %v", err)
+	}
+
+	e := experiment("scientisttest-budget", 2)
+	e.AfterRun(b.Publish)
+	e.Run()
+
+	ft := &fakeT{}
+	b.Check(ft)
+	if len(ft.errors) != 1 {
+		t.Fatalf("Unexpected failures: %q", ft.errors)
+	}
+
+	msg := ft.errors[0]
+	if !strings.Contains(msg, `"scientisttest-budget" mismatched 3 of 21 runs (14.29%), over the 10.00% budget`) {
+		t.Errorf("Unexpected failure: %s", msg)
+	}
+	if !strings.Contains(msg, "candidate mismatched 3 times:\n") {
+		t.Errorf("Expected diff in failure: %s", msg)
+	}
+}
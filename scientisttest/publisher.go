@@ -0,0 +1,95 @@
+package scientisttest
+
+import (
+	"scientist"
+	"sync"
+	"testing"
+)
+
+// Publisher records published results for tests. The zero value is ready to
+// use:
+//
+//	var p scientisttest.Publisher
+//	e.Publish(p.Publish)
+type Publisher struct {
+	mu      sync.Mutex
+	results []scientist.Result
+}
+
+func (p *Publisher) Publish(r scientist.Result) error {
+	p.mu.Lock()
+	p.results = append(p.results, r)
+	p.mu.Unlock()
+	return nil
+}
+
+// Results returns every published result, oldest first.
+func (p *Publisher) Results() []scientist.Result {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]scientist.Result(nil), p.results...)
+}
+
+// LastResult returns the most recently published result, or nil if none have
+// been published.
+func (p *Publisher) LastResult() *scientist.Result {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.results) == 0 {
+		return nil
+	}
+	r := p.results[len(p.results)-1]
+	return &r
+}
+
+func (p *Publisher) MismatchCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	count := 0
+	for _, r := range p.results {
+		if r.IsMismatched() {
+			count++
+		}
+	}
+	return count
+}
+
+func (p *Publisher) Reset() {
+	p.mu.Lock()
+	p.results = nil
+	p.mu.Unlock()
+}
+
+// AssertMatched fails the test if no results were published, or if any
+// result mismatched, with its diff.
+func (p *Publisher) AssertMatched(t testing.TB) {
+	t.Helper()
+
+	results := p.Results()
+	if len(results) == 0 {
+		t.Errorf("no results were published")
+	}
+
+	for _, r := range results {
+		if r.IsMismatched() {
+			t.Errorf("experiment %q mismatched:\n%s", r.Experiment.Name, r.Diff())
+		}
+	}
+}
+
+// AssertMismatched fails the test unless the last published result
+// mismatched.
+func (p *Publisher) AssertMismatched(t testing.TB) {
+	t.Helper()
+
+	r := p.LastResult()
+	switch {
+	case r == nil:
+		t.Errorf("no results were published")
+	case !r.IsMismatched():
+		t.Errorf("experiment %q matched", r.Experiment.Name)
+	}
+}
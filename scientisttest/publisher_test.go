@@ -0,0 +1,57 @@
+package scientisttest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPublisher(t *testing.T) {
+	var p Publisher
+	if p.LastResult() != nil {
+		t.Errorf("Unexpected last result")
+	}
+
+	ft := &fakeT{}
+	p.AssertMatched(ft)
+	p.AssertMismatched(ft)
+	if len(ft.errors) != 2 || ft.errors[0] != "no results were published" {
+		t.Errorf("Unexpected failures: %q", ft.errors)
+	}
+
+	e := experiment("scientisttest-publisher", 1)
+	e.Publish(p.Publish)
+	e.Run()
+	p.AssertMatched(t)
+
+	ft = &fakeT{}
+	p.AssertMismatched(ft)
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "matched") {
+		t.Errorf("Unexpected failures: %q", ft.errors)
+	}
+
+	e = experiment("scientisttest-publisher", 2)
+	e.Publish(p.Publish)
+	e.Run()
+	p.AssertMismatched(t)
+
+	if n := len(p.Results()); n != 2 {
+		t.Errorf("Expected 2 results, got %d", n)
+	}
+	if n := p.MismatchCount(); n != 1 {
+		t.Errorf("Expected 1 mismatch, got %d", n)
+	}
+	if v := p.LastResult().Candidates[0].Value; v != 2 {
+		t.Errorf("Unexpected last candidate value: %v", v)
+	}
+
+	ft = &fakeT{}
+	p.AssertMatched(ft)
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "candidate:\n") {
+		t.Errorf("Unexpected failures: %q", ft.errors)
+	}
+
+	p.Reset()
+	if p.LastResult() != nil || p.MismatchCount() != 0 {
+		t.Errorf("Expected no results after reset")
+	}
+}
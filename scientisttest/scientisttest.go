@@ -0,0 +1,62 @@
+// Package scientisttest turns experiments into equivalence tests. Run fails
+// the test when candidates don't match the control, or when the experiment
+// reports an error.
+package scientisttest
+
+import (
+	"scientist"
+	"sync"
+	"testing"
+)
+
+// Run runs the experiment and returns the control's value and error. The test
+// fails if any candidate mismatches, with a diff of each mismatched
+// candidate, or if any ResultError is reported. It also fails if the
+// experiment didn't run its candidates, like when its RunIf check returns
+// false.
+//
+// Run sets the experiment's ReportErrors callback and adds an AfterRun
+// callback, so pass it an experiment that hasn't run yet. Async experiments
+// are not supported.
+func Run(t testing.TB, e *scientist.Experiment) (interface{}, error) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var result *scientist.Result
+	var reported bool
+	e.AfterRun(func(r scientist.Result) error {
+		mu.Lock()
+		result = &r
+		mu.Unlock()
+		return nil
+	})
+
+	e.ReportErrors(func(errs ...scientist.ResultError) {
+		mu.Lock()
+		reported = true
+		mu.Unlock()
+
+		for _, err := range errs {
+			t.Errorf("experiment %q %s error: %v", err.Experiment, err.Operation, err.Err)
+		}
+	})
+
+	value, err := e.Run()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if result == nil {
+		// An invalid experiment has already failed the test.
+		if !reported {
+			t.Errorf("experiment %q didn't run its candidates", e.Name)
+		}
+		return value, err
+	}
+
+	if result.IsMismatched() {
+		t.Errorf("experiment %q mismatched:\n%s", e.Name, result.Diff())
+	}
+
+	return value, err
+}
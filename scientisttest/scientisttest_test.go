@@ -0,0 +1,96 @@
+package scientisttest
+
+import (
+	"errors"
+	"fmt"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Error(args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func experiment(name string, candidate int) *scientist.Experiment {
+	e := scientist.New(name)
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return candidate, nil
+	})
+	return e
+}
+
+func TestRun(t *testing.T) {
+	value, err := Run(t, experiment("scientisttest", 1))
+	if value != 1 || err != nil {
+		t.Errorf("Unexpected control: %v, %v", value, err)
+	}
+}
+
+func TestRunMismatch(t *testing.T) {
+	ft := &fakeT{}
+	value, _ := Run(ft, experiment("scientisttest-mismatch", 2))
+	if value != 1 {
+		t.Errorf("Unexpected control value: %v", value)
+	}
+
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "candidate:\n") {
+		t.Errorf("Unexpected failures: %q", ft.errors)
+	}
+}
+
+func TestRunError(t *testing.T) {
+	e := experiment("scientisttest-error", 1)
+	e.Publish(func(r scientist.Result) error {
+		return errors.New("boom")
+	})
+
+	ft := &fakeT{}
+	Run(ft, e)
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "publish error: boom") {
+		t.Errorf("Unexpected failures: %q", ft.errors)
+	}
+}
+
+func TestRunNotRun(t *testing.T) {
+	e := experiment("scientisttest-not-run", 1)
+	e.RunIf(func() (bool, error) {
+		return false, nil
+	})
+
+	ft := &fakeT{}
+	Run(ft, e)
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "didn't run") {
+		t.Errorf("Unexpected failures: %q", ft.errors)
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	e := scientist.New("scientisttest-invalid")
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	ft := &fakeT{}
+	if _, err := Run(ft, e); err == nil {
+		t.Errorf("Expected validation error")
+	}
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "validate error") {
+		t.Errorf("Unexpected failures: %q", ft.errors)
+	}
+}
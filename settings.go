@@ -0,0 +1,53 @@
+package scientist
+
+import "time"
+
+type Settings struct {
+	Enabled        *bool
+	Percent        *float64
+	Concurrent     *bool
+	Timeout        *time.Duration
+	PublishPercent *float64
+}
+
+func Configure(name string, s Settings) {
+	registered(name).settings.Store(&s)
+}
+
+func (e *Experiment) settings() *Settings {
+	s, _ := e.state.settings.Load().(*Settings)
+	return s
+}
+
+// configured returns a copy of the experiment with its configured settings,
+// so they override the experiment's own for a single run.
+func (e *Experiment) configured() *Experiment {
+	s := e.settings()
+	if s == nil {
+		return e
+	}
+
+	c := *e
+	if s.Percent != nil {
+		c.percent = *s.Percent
+	}
+
+	if s.Concurrent != nil {
+		c.concurrent = *s.Concurrent
+	}
+
+	if s.Timeout != nil {
+		c.timeout = *s.Timeout
+	}
+
+	if s.PublishPercent != nil {
+		c.publishPercent = *s.PublishPercent
+	}
+
+	return &c
+}
+
+func (e *Experiment) configDisabled() bool {
+	s := e.settings()
+	return s != nil && s.Enabled != nil && !*s.Enabled
+}
@@ -0,0 +1,62 @@
+package scientist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfigure(t *testing.T) {
+	enabled := false
+	percent := 0.0
+	defer Configure("configure", Settings{})
+
+	Configure("configure", Settings{Enabled: &enabled})
+
+	e := New("configure")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		time.Sleep(time.Second)
+		return 1, nil
+	})
+
+	var published Result
+	e.Publish(func(r Result) error {
+		published = r
+		return nil
+	})
+
+	if !e.Disabled() {
+		t.Errorf("expected experiment to be disabled")
+	}
+
+	enabled = true
+	Configure("configure", Settings{Enabled: &enabled, Percent: &percent})
+	if e.Disabled() {
+		t.Errorf("expected experiment to be enabled")
+	}
+
+	start := time.Now()
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("expected candidate not to run")
+	}
+
+	concurrent := true
+	timeout := 10 * time.Millisecond
+	Configure("configure", Settings{Concurrent: &concurrent, Timeout: &timeout})
+
+	start = time.Now()
+	e.Run()
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("expected candidate to time out")
+	}
+
+	if len(published.Candidates) != 1 || published.Candidates[0].Err == nil {
+		t.Errorf("Unexpected candidates: %v", published.Candidates)
+	}
+}
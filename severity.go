@@ -0,0 +1,61 @@
+package scientist
+
+import "errors"
+
+// Severity tells error reporters how urgent a ResultError is. Severities are
+// ordered, and the zero value is SeverityError.
+type Severity int
+
+const (
+	SeverityWarning Severity = iota - 1
+	SeverityError
+	SeverityCritical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "error"
+	}
+}
+
+// ClassifyErrors sets the Severity of every error the experiment reports or
+// publishes in a Result, replacing the default classification.
+func (e *Experiment) ClassifyErrors(fn func(ResultError) Severity) {
+	if !e.callback("ClassifyErrors", fn) {
+		return
+	}
+
+	e.classifier = fn
+}
+
+// DefaultSeverity treats panics as critical, and publishing failures and
+// expired experiments as warnings, since the control still ran.
+func DefaultSeverity(err ResultError) Severity {
+	var pe PanicError
+	if errors.As(err.Err, &pe) {
+		return SeverityCritical
+	}
+
+	if err.Operation == "publish" || err.Operation == "expired" {
+		return SeverityWarning
+	}
+
+	return SeverityError
+}
+
+// classify sets the error's Severity. Errors are classified as they're made,
+// so publishers see the same Severity as the error reporter.
+func (e *Experiment) classify(err ResultError) ResultError {
+	err.Severity = e.classifier(err)
+	return err
+}
+
+// report passes errors to the error reporter.
+func (e *Experiment) report(errs ...ResultError) {
+	e.errorReporter(errs...)
+}
@@ -0,0 +1,101 @@
+package scientist
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDefaultSeverity(t *testing.T) {
+	e := New("severity")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		panic("boom")
+	})
+	e.Publish(func(r Result) error {
+		return errors.New("publish")
+	})
+	e.Compare(func(control, candidate interface{}) (bool, error) {
+		return false, errors.New("compare")
+	})
+
+	severities := make(map[string]Severity)
+	e.ReportErrors(func(errs ...ResultError) {
+		for _, err := range errs {
+			severities[err.Operation] = err.Severity
+		}
+	})
+	e.Run()
+	e.ReportError("after_run", PanicError{Value: "boom"})
+
+	expected := map[string]Severity{
+		"compare":   SeverityError,
+		"publish":   SeverityWarning,
+		"after_run": SeverityCritical,
+	}
+	for op, severity := range expected {
+		if severities[op] != severity {
+			t.Errorf("Expected %s errors to be %s, got %s", op, severity, severities[op])
+		}
+	}
+}
+
+func TestClassifyErrors(t *testing.T) {
+	e := New("classify")
+	e.ClassifyErrors(func(err ResultError) Severity {
+		if err.Operation == "publish" {
+			return SeverityCritical
+		}
+		return SeverityWarning
+	})
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+	e.ReportError("publish", errors.New("boom"))
+
+	if len(reported) != 1 || reported[0].Severity != SeverityCritical {
+		t.Errorf("Unexpected errors: %v", reported)
+	}
+
+	if p := reported[0].Payload(); p.Severity != "critical" {
+		t.Errorf("Unexpected payload severity: %q", p.Severity)
+	}
+}
+
+func TestClassifyErrorsPublished(t *testing.T) {
+	e := New("classify-published")
+	e.ClassifyErrors(func(err ResultError) Severity {
+		if err.Operation == "compare" {
+			return SeverityCritical
+		}
+		return SeverityWarning
+	})
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Compare(func(control, candidate interface{}) (bool, error) {
+		return false, errors.New("compare")
+	})
+	e.ReportErrors(func(errs ...ResultError) {})
+
+	var published []ResultError
+	e.Publish(func(r Result) error {
+		published = r.Errors
+		return nil
+	})
+	e.Run()
+
+	if len(published) != 1 || published[0].Severity != SeverityCritical {
+		t.Fatalf("Expected publishers to see classified errors: %v", published)
+	}
+
+	if p := published[0].Payload(); p.Severity != "critical" {
+		t.Errorf("Unexpected payload severity: %q", p.Severity)
+	}
+}
@@ -0,0 +1,87 @@
+package scientist
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// cpuSampleInterval is how long CPUOver reuses a measurement, so checking it
+// on every run stays cheap.
+const cpuSampleInterval = 100 * time.Millisecond
+
+func (e *Experiment) SkipWhen(fn func() bool) {
+	if !e.callback("SkipWhen", fn) {
+		return
+	}
+
+	e.skipChecks = append(e.skipChecks, fn)
+}
+
+func (e *Experiment) shedding() bool {
+	for _, fn := range e.skipChecks {
+		if fn() {
+			return true
+		}
+	}
+	return false
+}
+
+func GoroutinesOver(n int) func() bool {
+	return func() bool {
+		return runtime.NumGoroutine() > n
+	}
+}
+
+// CPUOver reports whether the process used more than a percentage of the
+// available CPU since it last measured. It never reports true on platforms
+// without process CPU times.
+func CPUOver(percent float64) func() bool {
+	var mu sync.Mutex
+	var lastWall time.Time
+	var lastCPU time.Duration
+	var over bool
+
+	return func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		if !lastWall.IsZero() && now.Sub(lastWall) < cpuSampleInterval {
+			return over
+		}
+
+		cpu, ok := processCPUTime()
+		if !ok {
+			return false
+		}
+
+		if !lastWall.IsZero() {
+			available := now.Sub(lastWall).Seconds() * float64(runtime.GOMAXPROCS(0))
+			used := (cpu - lastCPU).Seconds() / math.Max(available, 1e-9) * 100
+			over = used > percent
+		}
+
+		lastWall = now
+		lastCPU = cpu
+		return over
+	}
+}
+
+func shedCandidates(e *Experiment, name string) []*Observation {
+	candidates := make([]*Observation, 0, len(e.behaviors)-1)
+	for _, bname := range behaviorNames(e) {
+		if bname == name {
+			continue
+		}
+
+		candidates = append(candidates, &Observation{
+			Experiment: e,
+			Name:       bname,
+			Started:    time.Now(),
+			SkipReason: SkipShed,
+		})
+	}
+	return candidates
+}
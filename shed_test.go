@@ -0,0 +1,76 @@
+package scientist
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSkipWhen(t *testing.T) {
+	shed := true
+
+	e := basicExperiment()
+	e.SkipWhen(func() bool {
+		return shed
+	})
+
+	var published []Result
+	e.Publish(func(r Result) error {
+		published = append(published, r)
+		return nil
+	})
+
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+
+	shed = false
+	e.Run()
+
+	if len(published) != 2 {
+		t.Fatalf("Unexpected published results: %d", len(published))
+	}
+
+	r := published[0]
+	assertObservationNames(t, "candidate", r.Candidates, []string{})
+	assertObservationNames(t, "skipped", r.Skipped, []string{"candidate", "correct", "three"})
+	for _, o := range r.Skipped {
+		if o.SkipReason != SkipShed {
+			t.Errorf("Unexpected skip reason for %q: %q", o.Name, o.SkipReason)
+		}
+	}
+
+	assertObservationNames(t, "candidate", published[1].Candidates, []string{"candidate", "correct", "three"})
+}
+
+func TestGoroutinesOver(t *testing.T) {
+	if !GoroutinesOver(0)() {
+		t.Errorf("expected more than 0 goroutines")
+	}
+
+	if GoroutinesOver(runtime.NumGoroutine() + 1000)() {
+		t.Errorf("expected fewer goroutines")
+	}
+}
+
+func TestCPUOver(t *testing.T) {
+	if _, ok := processCPUTime(); !ok {
+		t.Skip("process CPU time isn't available")
+	}
+
+	over := CPUOver(0)
+	if over() {
+		t.Errorf("expected the first measurement to be under")
+	}
+
+	for start := time.Now(); time.Since(start) < 2*cpuSampleInterval; {
+	}
+
+	if !over() {
+		t.Errorf("expected a busy loop to use some CPU")
+	}
+
+	if CPUOver(100)() {
+		t.Errorf("expected the first measurement to be under")
+	}
+}
@@ -0,0 +1,99 @@
+package scientist
+
+import (
+	"context"
+	"sync"
+)
+
+// background counts async candidates and comparisons that are still running.
+// Unlike a sync.WaitGroup, it can be waited on while new runs start.
+var background = newInflight()
+
+type inflight struct {
+	mu      sync.Mutex
+	running int
+	idle    *sync.Cond
+}
+
+func newInflight() *inflight {
+	f := &inflight{}
+	f.idle = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *inflight) add(delta int) {
+	f.mu.Lock()
+	f.running += delta
+	if f.running == 0 {
+		f.idle.Broadcast()
+	}
+	f.mu.Unlock()
+}
+
+func (f *inflight) wait() {
+	f.mu.Lock()
+	for f.running > 0 {
+		f.idle.Wait()
+	}
+	f.mu.Unlock()
+}
+
+var queues struct {
+	sync.Mutex
+	m map[*PublishQueue]struct{}
+}
+
+// Shutdown waits for async candidates and comparisons to finish, and then for
+// every open PublishQueue to publish what's queued, so results aren't lost on
+// deploys. If ctx is done first, Shutdown returns its error and leaves the
+// rest running. Experiments can still run after Shutdown.
+func Shutdown(ctx context.Context) error {
+	if err := wait(ctx, background.wait); err != nil {
+		return err
+	}
+
+	queues.Lock()
+	open := make([]*PublishQueue, 0, len(queues.m))
+	for q := range queues.m {
+		open = append(open, q)
+	}
+	queues.Unlock()
+
+	for _, q := range open {
+		if err := wait(ctx, q.Flush); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func wait(ctx context.Context, fn func()) error {
+	done := make(chan struct{})
+	go func() {
+		fn()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func trackQueue(q *PublishQueue) {
+	queues.Lock()
+	if queues.m == nil {
+		queues.m = make(map[*PublishQueue]struct{})
+	}
+	queues.m[q] = struct{}{}
+	queues.Unlock()
+}
+
+func untrackQueue(q *PublishQueue) {
+	queues.Lock()
+	delete(queues.m, q)
+	queues.Unlock()
+}
@@ -0,0 +1,64 @@
+package scientist
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShutdown(t *testing.T) {
+	var published int32
+	q := NewPublishQueue(func(r Result) error {
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&published, 1)
+		return nil
+	}, 10, 1, Block)
+	defer q.Close()
+
+	e := New("shutdown")
+	e.EnableAsync()
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return 1, nil
+	})
+	e.Publish(q.Publish)
+
+	for i := 0; i < 3; i++ {
+		e.Run()
+	}
+
+	if err := Shutdown(context.Background()); err != nil {
+		t.Fatalf("Unexpected shutdown error: %v", err)
+	}
+
+	if n := atomic.LoadInt32(&published); n != 3 {
+		t.Errorf("Expected 3 published results, got %d", n)
+	}
+}
+
+func TestShutdownDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	e := New("shutdown-deadline")
+	e.EnableAsync()
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		<-release
+		return 1, nil
+	})
+	e.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Unexpected shutdown error: %v", err)
+	}
+}
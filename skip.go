@@ -0,0 +1,17 @@
+package scientist
+
+const (
+	SkipPercent = "percent"
+	SkipRunIf   = "run_if"
+)
+
+// PublishSkipped publishes the runs that RunPercent or RunIf skip, with a
+// SkipPercent or SkipRunIf skip reason. Only the control runs, like it would
+// anyway, so the published results can count every call.
+func (e *Experiment) PublishSkipped() {
+	if !e.configurable("PublishSkipped") {
+		return
+	}
+
+	e.publishSkipped = true
+}
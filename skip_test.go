@@ -0,0 +1,79 @@
+package scientist
+
+import "testing"
+
+func TestPublishSkipped(t *testing.T) {
+	run := true
+	e := New("publish-skipped")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		t.Errorf("Expected the candidate to be skipped")
+		return 1, nil
+	})
+	e.RunIf(func() (bool, error) {
+		return run, nil
+	})
+	e.RunPercent(0)
+	e.PublishSkipped()
+
+	var results []Result
+	e.Publish(func(r Result) error {
+		results = append(results, r)
+		return nil
+	})
+
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control: %v, %v", v, err)
+	}
+
+	if len(results) != 1 || results[0].SkipReason != SkipPercent {
+		t.Fatalf("Expected a result skipped by percent: %v", results)
+	}
+
+	if results[0].Control.Value != 1 || len(results[0].Candidates) != 0 {
+		t.Errorf("Unexpected skipped result: %v", results[0])
+	}
+}
+
+func TestPublishSkippedRunIf(t *testing.T) {
+	e := New("publish-skipped-run-if")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.RunIf(func() (bool, error) {
+		return false, nil
+	})
+	e.PublishSkipped()
+
+	var results []Result
+	e.Publish(func(r Result) error {
+		results = append(results, r)
+		return nil
+	})
+	e.Run()
+
+	if len(results) != 1 || results[0].SkipReason != SkipRunIf {
+		t.Errorf("Expected a result skipped by RunIf: %v", results)
+	}
+}
+
+func TestSkippedNotPublishedByDefault(t *testing.T) {
+	e := New("skipped-unpublished")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.RunPercent(0)
+	e.Publish(func(r Result) error {
+		t.Errorf("Unexpected published result: %v", r)
+		return nil
+	})
+	e.Run()
+}
@@ -0,0 +1,80 @@
+package scientist
+
+import (
+	"context"
+	"log/slog"
+)
+
+func LogPublisher(logger *slog.Logger) func(Result) error {
+	return func(r Result) error {
+		level := slog.LevelInfo
+		if r.IsMismatched() {
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("experiment", r.Experiment.Name),
+			slog.Bool("matched", r.IsMatched()),
+			slog.Bool("mismatched", r.IsMismatched()),
+			slog.Bool("ignored", r.IsIgnored()),
+		}
+
+		if r.SkipReason != "" {
+			attrs = append(attrs, slog.String("skip_reason", r.SkipReason))
+		}
+
+		for _, o := range r.Observations {
+			attrs = append(attrs, observationAttr(r, o))
+		}
+
+		logOrDefault(logger).LogAttrs(r.Context(), level, "[scientist] experiment result", attrs...)
+		return nil
+	}
+}
+
+func LogErrors(logger *slog.Logger) func(...ResultError) {
+	return func(errs ...ResultError) {
+		for _, err := range errs {
+			logOrDefault(logger).LogAttrs(context.Background(), severityLevel(err.Severity), "[scientist] experiment error",
+				slog.String("experiment", err.Experiment),
+				slog.String("operation", err.Operation),
+				slog.Any("error", err.Err),
+			)
+		}
+	}
+}
+
+func observationAttr(r Result, o *Observation) slog.Attr {
+	attrs := []interface{}{slog.Duration("runtime", o.Runtime)}
+	if o.Err != nil {
+		attrs = append(attrs, slog.Any("error", o.Err))
+	}
+
+	for _, m := range r.Mismatched {
+		if m == o {
+			attrs = append(attrs, slog.Bool("mismatched", true))
+		}
+	}
+
+	return slog.Group(o.Name, attrs...)
+}
+
+// logOrDefault looks up the default logger as late as possible, so
+// slog.SetDefault() can be called after experiments are defined.
+func logOrDefault(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
+func severityLevel(s Severity) slog.Level {
+	switch s {
+	case SeverityWarning:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelError
+	}
+}
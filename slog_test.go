@@ -0,0 +1,55 @@
+package scientist
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestLogPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	e := basicExperiment()
+	e.Publish(LogPublisher(logger))
+	e.Run()
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("bad log entry %q: %v", buf.String(), err)
+	}
+
+	if entry["level"] != "WARN" || entry["experiment"] != "basic" || entry["mismatched"] != true {
+		t.Errorf("Unexpected log entry: %v", entry)
+	}
+
+	three, ok := entry["three"].(map[string]interface{})
+	if !ok || three["mismatched"] != true {
+		t.Errorf("Unexpected observation entry: %v", entry["three"])
+	}
+
+	if control, ok := entry["control"].(map[string]interface{}); !ok || control["runtime"] == nil {
+		t.Errorf("Unexpected observation entry: %v", entry["control"])
+	}
+}
+
+func TestLogErrors(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	e := New("log")
+	e.ReportErrors(LogErrors(logger))
+	e.ReportError("publish", errors.New("boom"))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("bad log entry %q: %v", buf.String(), err)
+	}
+
+	// publishing failures are only warnings by default
+	if entry["level"] != "WARN" || entry["experiment"] != "log" || entry["operation"] != "publish" || entry["error"] != "boom" {
+		t.Errorf("Unexpected log entry: %v", entry)
+	}
+}
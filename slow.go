@@ -0,0 +1,78 @@
+package scientist
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrSlowCandidate = errors.New("[scientist] candidate ran too long compared to the control")
+
+type controlKey struct{}
+
+// controlRuntime tells candidates how long the control took, whether it ran
+// before them or alongside them.
+type controlRuntime struct {
+	done    chan struct{}
+	runtime time.Duration
+}
+
+// CancelSlowCandidates cancels candidates once they've run factor times as
+// long as the control, even without a timeout. Candidates running alongside
+// the control learn their budget when it finishes.
+func (e *Experiment) CancelSlowCandidates(factor float64) {
+	if !e.configurable("CancelSlowCandidates") {
+		return
+	}
+
+	e.slowFactor = factor
+}
+
+func withControlRuntime(ctx context.Context, e *Experiment) context.Context {
+	if e.slowFactor <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, controlKey{}, &controlRuntime{done: make(chan struct{})})
+}
+
+func finishControl(ctx context.Context, e *Experiment, control *Observation) {
+	if e.slowFactor <= 0 {
+		return
+	}
+
+	if c, ok := ctx.Value(controlKey{}).(*controlRuntime); ok {
+		c.runtime = control.Runtime
+		close(c.done)
+	}
+}
+
+// slowContext cancels ctx with ErrSlowCandidate once the candidate has used up
+// its share of the control's runtime.
+func slowContext(ctx context.Context, e *Experiment) (context.Context, context.CancelFunc) {
+	c, ok := ctx.Value(controlKey{}).(*controlRuntime)
+	if e.slowFactor <= 0 || !ok {
+		return ctx, func() {}
+	}
+
+	started := time.Now()
+	ctx, cancel := context.WithCancelCause(ctx)
+	go func() {
+		select {
+		case <-c.done:
+		case <-ctx.Done():
+			return
+		}
+
+		budget := time.Duration(float64(c.runtime)*e.slowFactor) - time.Since(started)
+		timer := time.NewTimer(budget)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			cancel(ErrSlowCandidate)
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() { cancel(context.Canceled) }
+}
@@ -0,0 +1,62 @@
+package scientist
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCancelSlowCandidates(t *testing.T) {
+	for _, mode := range []string{"concurrent", "control-first"} {
+		t.Run(mode, func(t *testing.T) {
+			e := New("slow-" + mode)
+			if mode == "concurrent" {
+				e.EnableConcurrency(0)
+			} else {
+				e.EnableControlFirst(0)
+			}
+			e.CancelSlowCandidates(2)
+			e.Use(func() (interface{}, error) {
+				time.Sleep(10 * time.Millisecond)
+				return 1, nil
+			})
+			e.TryContext(func(ctx context.Context) (interface{}, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+			e.Behavior("fast", func() (interface{}, error) {
+				return 1, nil
+			})
+
+			r := Run(e, "control")
+			assertObservationNames(t, "timed out", r.TimedOut, []string{"candidate"})
+
+			o := r.TimedOut[0]
+			if o.Err != ErrSlowCandidate {
+				t.Errorf("Unexpected candidate error: %v", o.Err)
+			}
+
+			if o.Runtime < 10*time.Millisecond || o.Runtime > time.Second {
+				t.Errorf("Unexpected candidate runtime: %v", o.Runtime)
+			}
+		})
+	}
+}
+
+func TestCancelSlowCandidatesSequential(t *testing.T) {
+	e := New("slow-sequential")
+	e.CancelSlowCandidates(2)
+	e.Use(func() (interface{}, error) {
+		time.Sleep(10 * time.Millisecond)
+		return 1, nil
+	})
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, context.Cause(ctx)
+	})
+
+	r := Run(e, "control")
+	if err := r.Candidates[0].Err; err != ErrSlowCandidate {
+		t.Errorf("Unexpected candidate error: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+// Package statsd publishes scientist results to StatsD or DogStatsD.
+//
+// Every result increments one of these counters:
+//
+//	scientist.<experiment>.matched
+//	scientist.<experiment>.mismatched
+//	scientist.<experiment>.ignored
+//
+// RunVariant results aren't compared, so they increment a counter for the
+// behavior that ran instead:
+//
+//	scientist.<experiment>.variant.<behavior>
+//
+// and records a timing for each observation:
+//
+//	scientist.<experiment>.<behavior>
+package statsd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"scientist"
+	"strings"
+	"time"
+)
+
+type Publisher struct {
+	// Prefix is prepended to every metric name. It defaults to "scientist".
+	Prefix string
+
+	// Tags are appended to every metric in the DogStatsD format.
+	Tags []string
+
+	w io.Writer
+}
+
+func Dial(addr string) (*Publisher, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return New(conn), nil
+}
+
+func New(w io.Writer) *Publisher {
+	return &Publisher{Prefix: "scientist", w: w}
+}
+
+func (p *Publisher) Publish(r scientist.Result) error {
+	var buf bytes.Buffer
+	name := sanitize(r.Experiment.Name)
+
+	switch {
+	case r.SkipReason != "":
+		p.count(&buf, name, sanitize(r.SkipReason))
+	case r.Variant != "":
+		p.count(&buf, name, "variant."+sanitize(r.Variant))
+	case r.IsMismatched():
+		p.count(&buf, name, "mismatched")
+	case r.IsIgnored():
+		p.count(&buf, name, "ignored")
+	default:
+		p.count(&buf, name, "matched")
+	}
+
+	for _, o := range r.Observations {
+		p.timing(&buf, name, sanitize(o.Name), o.Runtime)
+	}
+
+	_, err := p.w.Write(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}))
+	return err
+}
+
+func (p *Publisher) Close() error {
+	if c, ok := p.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (p *Publisher) count(buf *bytes.Buffer, experiment, metric string) {
+	fmt.Fprintf(buf, "%s:1|c", p.metric(experiment, metric))
+	p.tags(buf)
+}
+
+func (p *Publisher) timing(buf *bytes.Buffer, experiment, metric string, d time.Duration) {
+	fmt.Fprintf(buf, "%s:%g|ms", p.metric(experiment, metric), float64(d)/float64(time.Millisecond))
+	p.tags(buf)
+}
+
+func (p *Publisher) metric(experiment, metric string) string {
+	if len(p.Prefix) == 0 {
+		return experiment + "." + metric
+	}
+	return p.Prefix + "." + experiment + "." + metric
+}
+
+func (p *Publisher) tags(buf *bytes.Buffer) {
+	if len(p.Tags) > 0 {
+		buf.WriteString("|#")
+		buf.WriteString(strings.Join(p.Tags, ","))
+	}
+	buf.WriteByte('\n')
+}
+
+var replacer = strings.NewReplacer(":", "_", "|", "_", "@", "_", "#", "_", " ", "_", "\n", "_")
+
+func sanitize(name string) string {
+	return replacer.Replace(name)
+}
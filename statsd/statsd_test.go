@@ -0,0 +1,63 @@
+package statsd
+
+import (
+	"bytes"
+	"scientist"
+	"strings"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf)
+	p.Tags = []string{"env:test"}
+
+	e := scientist.New("widget permissions")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Publish(p.Publish)
+	e.Run()
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Unexpected metrics: %q", buf.String())
+	}
+
+	if lines[0] != "scientist.widget_permissions.mismatched:1|c|#env:test" {
+		t.Errorf("Unexpected counter: %q", lines[0])
+	}
+
+	for i, name := range []string{"control", "candidate"} {
+		prefix := "scientist.widget_permissions." + name + ":"
+		line := lines[i+1]
+		if !strings.HasPrefix(line, prefix) || !strings.HasSuffix(line, "|ms|#env:test") {
+			t.Errorf("Unexpected %s timing: %q", name, line)
+		}
+	}
+}
+
+func TestPublishVariant(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(&buf)
+
+	e := scientist.New("variant")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+	e.Publish(p.Publish)
+
+	variant := e.Variant("key")
+	e.RunVariant("key")
+
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != 2 || lines[0] != "scientist.variant.variant."+variant+":1|c" {
+		t.Errorf("Unexpected metrics: %q", buf.String())
+	}
+}
@@ -0,0 +1,266 @@
+package scientist
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// streamBuffer is how many chunks of the control can be waiting to be
+// compared before reading the control waits for the candidates.
+const streamBuffer = 64
+
+// Stream summarizes a streamed value from a behavior that returned an
+// io.Reader, after CompareStreams read it.
+type Stream struct {
+	// Bytes is how many bytes were read.
+	Bytes int64
+
+	// DiffersAt is the offset of the first byte that differs from the
+	// control, or -1 if none do. It's always -1 for the control.
+	DiffersAt int64
+
+	// Partial is set when the control wasn't read to the end, so only what
+	// was read is compared.
+	Partial bool
+}
+
+// CompareStreams compares behaviors that return an io.Reader or
+// io.ReadCloser without buffering them. Run returns a reader that passes the
+// control's stream through unchanged, while each candidate is read alongside
+// it in chunks. Once the control is read to the end or closed, each
+// behavior's value is replaced with a Stream, and the result is compared and
+// published in the background.
+//
+// The control must be read to the end or closed, like an HTTP response body,
+// or the result is never published. A candidate that reads slowly slows down
+// reading the control.
+func (e *Experiment) CompareStreams() {
+	if !e.configurable("CompareStreams") {
+		return
+	}
+
+	e.streams = true
+}
+
+// runStreams observes every behavior, and compares their streams while the
+// caller reads the control.
+func runStreams(e *Experiment, name string, opts runOptions) Result {
+	r := observeRun(e, name, opts)
+	control := *r.Control
+	partial := r
+	partial.Control = &control
+	partial.Candidates = nil
+
+	tee := &streamTee{done: make(chan struct{})}
+	src, ok := r.Control.Value.(io.Reader)
+	if ok && r.Control.Err == nil {
+		tee.src = src
+		control.returned = tee
+		control.substituted = true
+	}
+
+	var wg sync.WaitGroup
+	summaries := make([]*Stream, len(r.Candidates))
+	for i, c := range r.Candidates {
+		candidate, ok := c.Value.(io.Reader)
+		if !ok || c.Err != nil {
+			continue
+		}
+
+		chunks := make(chan []byte, streamBuffer)
+		tee.outs = append(tee.outs, chunks)
+		wg.Add(1)
+		go func(i int, c *Observation) {
+			defer wg.Done()
+			s, err := compareStream(chunks, candidate, tee)
+			if err != nil {
+				c.Err = err
+			}
+			summaries[i] = &s
+		}(i, c)
+	}
+
+	if tee.src == nil {
+		tee.finish(io.EOF, true)
+	}
+
+	background.add(1)
+	go func() {
+		defer background.add(-1)
+		<-tee.done
+		wg.Wait()
+
+		if tee.src != nil {
+			r.Control.Value = Stream{Bytes: tee.read, DiffersAt: -1, Partial: !tee.complete}
+		}
+		for i, s := range summaries {
+			if s != nil {
+				r.Candidates[i].Value = *s
+			}
+		}
+		conclude(r)
+	}()
+
+	return partial
+}
+
+// streamTee passes the control's stream through to the caller, sending each
+// chunk it reads to the candidates' comparisons.
+type streamTee struct {
+	src  io.Reader
+	outs []chan []byte
+	done chan struct{}
+
+	// readMu serializes reads, so chunks are sent in order. It's held while
+	// reading from src, so Close doesn't take it.
+	readMu sync.Mutex
+
+	// mu guards the rest. A finish while a read is in flight waits in
+	// pending until the read is done sending, so chunks aren't sent once the
+	// outs are closed.
+	mu       sync.Mutex
+	reading  bool
+	pending  *streamFinish
+	finished bool
+	err      error
+	read     int64
+	complete bool
+}
+
+type streamFinish struct {
+	err      error
+	complete bool
+}
+
+// Read returns the error that finished the stream, or io.EOF after Close,
+// once it's finished.
+func (t *streamTee) Read(p []byte) (int, error) {
+	t.readMu.Lock()
+	defer t.readMu.Unlock()
+
+	t.mu.Lock()
+	if t.finished {
+		t.mu.Unlock()
+		return 0, t.err
+	}
+	t.reading = true
+	t.mu.Unlock()
+
+	n, err := t.src.Read(p)
+	if n > 0 {
+		chunk := append([]byte(nil), p[:n]...)
+		for _, out := range t.outs {
+			out <- chunk
+		}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reading = false
+	t.read += int64(n)
+	switch {
+	case t.pending != nil:
+		t.finishLocked(t.pending.err, t.pending.complete)
+	case err != nil:
+		t.finishLocked(err, err == io.EOF)
+	}
+	return n, err
+}
+
+// Close finishes the stream and closes the control, which can unblock a Read
+// waiting on it.
+func (t *streamTee) Close() error {
+	t.finish(io.EOF, false)
+	if c, ok := t.src.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (t *streamTee) finish(err error, complete bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.reading {
+		if t.pending == nil {
+			t.pending = &streamFinish{err: err, complete: complete}
+		}
+		return
+	}
+	t.finishLocked(err, complete)
+}
+
+func (t *streamTee) finishLocked(err error, complete bool) {
+	if t.finished {
+		return
+	}
+
+	t.finished = true
+	t.err = err
+	t.complete = complete
+	for _, out := range t.outs {
+		close(out)
+	}
+	close(t.done)
+}
+
+// compareStream reads the candidate alongside the control's chunks. It keeps
+// receiving chunks after a difference, so the control is never held up.
+func compareStream(chunks <-chan []byte, candidate io.Reader, tee *streamTee) (Stream, error) {
+	defer func() {
+		if c, ok := candidate.(io.Closer); ok {
+			c.Close()
+		}
+	}()
+
+	s := Stream{DiffersAt: -1}
+	var offset int64
+	var readErr error
+	var buf []byte
+	for chunk := range chunks {
+		if s.DiffersAt < 0 && readErr == nil {
+			if cap(buf) < len(chunk) {
+				buf = make([]byte, len(chunk))
+			}
+
+			n, err := io.ReadFull(candidate, buf[:len(chunk)])
+			s.Bytes += int64(n)
+			if i := firstDifference(chunk[:n], buf[:n]); i >= 0 {
+				s.DiffersAt = offset + int64(i)
+			} else if n < len(chunk) {
+				s.DiffersAt = offset + int64(n)
+			}
+
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				readErr = err
+			}
+		}
+		offset += int64(len(chunk))
+	}
+
+	// chunks is closed, so the tee is finished.
+	s.Partial = !tee.complete
+	if s.DiffersAt < 0 && readErr == nil && tee.complete {
+		var extra [1]byte
+		if n, _ := io.ReadFull(candidate, extra[:]); n > 0 {
+			s.Bytes += int64(n)
+			s.DiffersAt = offset
+		}
+	}
+
+	return s, readErr
+}
+
+func firstDifference(a, b []byte) int {
+	if bytes.Equal(a, b) {
+		return -1
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return -1
+}
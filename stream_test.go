@@ -0,0 +1,239 @@
+package scientist
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestCompareStreams(t *testing.T) {
+	body := strings.Repeat("0123456789", 10000)
+	changed := []byte(body)
+	changed[54321] = 'x'
+
+	candidate := &closeRecorder{Reader: strings.NewReader(body)}
+	published := make(chan Result, 1)
+
+	e := New("compare-streams")
+	e.CompareStreams()
+	e.Use(func() (interface{}, error) {
+		return io.NopCloser(strings.NewReader(body)), nil
+	})
+	e.Try(func() (interface{}, error) {
+		return candidate, nil
+	})
+	e.Behavior("changed", func() (interface{}, error) {
+		return bytes.NewReader(changed), nil
+	})
+	e.Behavior("short", func() (interface{}, error) {
+		return strings.NewReader(body[:100]), nil
+	})
+	e.Publish(func(r Result) error {
+		published <- r
+		return nil
+	})
+
+	v, err := e.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-published:
+		t.Fatalf("Expected the result to wait for the control to be read")
+	default:
+	}
+
+	rc, ok := v.(io.ReadCloser)
+	if !ok {
+		t.Fatalf("Expected a stream, got %T", v)
+	}
+
+	got, err := io.ReadAll(rc)
+	if err != nil || string(got) != body {
+		t.Fatalf("Expected the control stream to be unchanged: %d bytes, %v", len(got), err)
+	}
+	if n, err := rc.Read(make([]byte, 10)); n != 0 || err != io.EOF {
+		t.Errorf("Expected reads after EOF to return io.EOF: %d, %v", n, err)
+	}
+	rc.Close()
+
+	var r Result
+	select {
+	case r = <-published:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the result to be published")
+	}
+
+	if r.Control.Value != (Stream{Bytes: int64(len(body)), DiffersAt: -1}) {
+		t.Errorf("Unexpected control: %+v", r.Control.Value)
+	}
+
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"changed", "short"})
+
+	for _, c := range r.Candidates {
+		s := c.Value.(Stream)
+		switch c.Name {
+		case "candidate":
+			if s.DiffersAt != -1 || s.Bytes != int64(len(body)) {
+				t.Errorf("Unexpected candidate: %+v", s)
+			}
+		case "changed":
+			if s.DiffersAt != 54321 {
+				t.Errorf("Unexpected changed candidate: %+v", s)
+			}
+		case "short":
+			if s.DiffersAt != 100 || s.Bytes != 100 {
+				t.Errorf("Unexpected short candidate: %+v", s)
+			}
+		}
+	}
+
+	if !candidate.closed {
+		t.Errorf("Expected the candidate to be closed")
+	}
+}
+
+func TestCompareStreamsPartial(t *testing.T) {
+	published := make(chan Result, 1)
+
+	e := New("compare-streams-partial")
+	e.CompareStreams()
+	e.Use(func() (interface{}, error) {
+		return strings.NewReader("hello world"), nil
+	})
+	e.Try(func() (interface{}, error) {
+		return strings.NewReader("hello there"), nil
+	})
+	e.Publish(func(r Result) error {
+		published <- r
+		return nil
+	})
+
+	v, _ := e.Run()
+	rc := v.(io.ReadCloser)
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(rc, buf); err != nil || string(buf) != "hello" {
+		t.Fatalf("Unexpected read: %q, %v", buf, err)
+	}
+	rc.Close()
+
+	if n, err := rc.Read(buf); n != 0 || err != io.EOF {
+		t.Errorf("Expected reads after Close to return io.EOF: %d, %v", n, err)
+	}
+
+	r := <-published
+	if !r.IsMatched() {
+		t.Errorf("Expected what was read to match: %+v", r.Candidates[0].Value)
+	}
+
+	if s := r.Control.Value.(Stream); !s.Partial || s.Bytes != 5 {
+		t.Errorf("Unexpected control: %+v", s)
+	}
+}
+
+func TestCompareStreamsControlError(t *testing.T) {
+	published := make(chan Result, 1)
+
+	e := New("compare-streams-control-error")
+	e.CompareStreams()
+	e.Use(func() (interface{}, error) {
+		return nil, errors.New("boom")
+	})
+	e.Try(func() (interface{}, error) {
+		return strings.NewReader("hello"), nil
+	})
+	e.Publish(func(r Result) error {
+		published <- r
+		return nil
+	})
+
+	if _, err := e.Run(); err == nil || err.Error() != "boom" {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	select {
+	case r := <-published:
+		assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate"})
+	case <-time.After(time.Second):
+		t.Fatal("Expected the result to be published")
+	}
+}
+
+func TestCompareStreamsCloseDuringRead(t *testing.T) {
+	published := make(chan Result, 1)
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	e := New("compare-streams-close-during-read")
+	e.CompareStreams()
+	e.Use(func() (interface{}, error) {
+		return pr, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return strings.NewReader("hello"), nil
+	})
+	e.Publish(func(r Result) error {
+		published <- r
+		return nil
+	})
+
+	v, _ := e.Run()
+	rc := v.(io.ReadCloser)
+
+	reading := make(chan error, 1)
+	go func() {
+		_, err := rc.Read(make([]byte, 5))
+		reading <- err
+	}()
+
+	// give the read time to block on the pipe.
+	time.Sleep(10 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		rc.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close not to wait for a blocked Read")
+	}
+
+	select {
+	case err := <-reading:
+		if err == nil {
+			t.Errorf("Expected the blocked Read to fail once closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Close to unblock the Read")
+	}
+
+	if n, err := rc.Read(make([]byte, 5)); n != 0 || err != io.EOF {
+		t.Errorf("Expected reads after Close to return io.EOF: %d, %v", n, err)
+	}
+
+	select {
+	case r := <-published:
+		if s := r.Control.Value.(Stream); !s.Partial || s.Bytes != 0 {
+			t.Errorf("Unexpected control: %+v", s)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the result to be published")
+	}
+}
@@ -0,0 +1,66 @@
+package scientist
+
+import (
+	"context"
+	"sync"
+)
+
+type tagsKey struct{}
+
+type tags struct {
+	sync.Mutex
+	m map[string]string
+}
+
+// Tag annotates the observation of the behavior running with ctx, like which
+// code path it took. Tags are published with the observation. Outside of an
+// observed behavior, Tag does nothing.
+func Tag(ctx context.Context, key, value string) {
+	t, ok := ctx.Value(tagsKey{}).(*tags)
+	if !ok {
+		return
+	}
+
+	t.Lock()
+	if t.m == nil {
+		t.m = make(map[string]string)
+	}
+	t.m[key] = value
+	t.Unlock()
+}
+
+// Observed returns whether ctx belongs to an observed behavior, whose outcome
+// is compared. Controls of runs that skip their candidates aren't observed,
+// so they can skip work that's only needed for the comparison.
+func Observed(ctx context.Context) bool {
+	_, ok := ctx.Value(tagsKey{}).(*tags)
+	return ok
+}
+
+// observation allocates an Observation together with its tags, saving an
+// allocation for every behavior.
+type observation struct {
+	Observation
+	tags tags
+}
+
+func withTags(ctx context.Context, t *tags) context.Context {
+	return context.WithValue(ctx, tagsKey{}, t)
+}
+
+// values copies the tags, since goroutines started by the behavior may still
+// be adding to them.
+func (t *tags) values() map[string]string {
+	t.Lock()
+	defer t.Unlock()
+
+	if len(t.m) == 0 {
+		return nil
+	}
+
+	m := make(map[string]string, len(t.m))
+	for k, v := range t.m {
+		m[k] = v
+	}
+	return m
+}
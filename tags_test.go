@@ -0,0 +1,47 @@
+package scientist
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTag(t *testing.T) {
+	e := New("tags")
+	e.UseContext(func(ctx context.Context) (interface{}, error) {
+		Tag(ctx, "path", "slow")
+		return 1, nil
+	})
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		Tag(ctx, "cache", "hit")
+		Tag(ctx, "path", "fast")
+		return 1, nil
+	})
+
+	r := Run(e, "control")
+	if tags := r.Control.Tags; len(tags) != 1 || tags["path"] != "slow" {
+		t.Errorf("Unexpected control tags: %v", tags)
+	}
+
+	p := r.Candidates[0].Payload()
+	if p.Tags["cache"] != "hit" || p.Tags["path"] != "fast" {
+		t.Errorf("Unexpected candidate tags: %v", p.Tags)
+	}
+}
+
+func TestTagWithoutExperiment(t *testing.T) {
+	// doesn't panic
+	Tag(context.Background(), "path", "slow")
+
+	e := New("untagged")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	r := Run(e, "control")
+	if r.Control.Tags != nil || r.Candidates[0].Tags != nil {
+		t.Errorf("Expected no tags")
+	}
+}
@@ -0,0 +1,33 @@
+package scientist
+
+import (
+	"context"
+	"runtime/trace"
+)
+
+// traceRun starts an execution trace task for the whole run. Tracing is
+// skipped entirely unless an execution trace is being collected.
+func traceRun(ctx context.Context, e *Experiment) (context.Context, func()) {
+	if !trace.IsEnabled() {
+		return ctx, func() {}
+	}
+
+	ctx, task := trace.NewTask(ctx, "scientist "+e.Name)
+	return ctx, task.End
+}
+
+// traceBehavior starts a task and a region for a behavior, so it shows up in
+// both the task and goroutine views of go tool trace.
+func traceBehavior(ctx context.Context, e *Experiment, name string) (context.Context, func()) {
+	if !trace.IsEnabled() {
+		return ctx, func() {}
+	}
+
+	label := e.Name + "." + name
+	ctx, task := trace.NewTask(ctx, label)
+	region := trace.StartRegion(ctx, label)
+	return ctx, func() {
+		region.End()
+		task.End()
+	}
+}
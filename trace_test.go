@@ -0,0 +1,49 @@
+package scientist
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"runtime/trace"
+	"testing"
+)
+
+func TestTraceBehaviors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := trace.Start(&buf); err != nil {
+		t.Skipf("tracing is unavailable: %v", err)
+	}
+
+	e := basicExperiment()
+	e.Run()
+	trace.Stop()
+
+	for _, name := range []string{"scientist basic", "basic.control", "basic.candidate", "basic.three"} {
+		if !bytes.Contains(buf.Bytes(), []byte(name)) {
+			t.Errorf("expected %q in the execution trace", name)
+		}
+	}
+}
+
+func TestProfileLabels(t *testing.T) {
+	labels := make(map[string]string)
+
+	e := New("labels")
+	e.UseContext(func(ctx context.Context) (interface{}, error) {
+		experiment, _ := pprof.Label(ctx, "experiment")
+		behavior, _ := pprof.Label(ctx, "behavior")
+		labels[behavior] = experiment
+		return 1, nil
+	})
+	e.TryContext(func(ctx context.Context) (interface{}, error) {
+		experiment, _ := pprof.Label(ctx, "experiment")
+		behavior, _ := pprof.Label(ctx, "behavior")
+		labels[behavior] = experiment
+		return 1, nil
+	})
+	e.Run()
+
+	if len(labels) != 2 || labels["control"] != "labels" || labels["candidate"] != "labels" {
+		t.Errorf("Unexpected profile labels: %v", labels)
+	}
+}
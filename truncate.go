@@ -0,0 +1,68 @@
+package scientist
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+)
+
+// truncate shortens strings and byte slices to max bytes, and slices,
+// arrays, and maps to max elements. A marker with the number of dropped bytes
+// or elements is appended, so truncated values aren't mistaken for real ones.
+func truncate(v interface{}, max int) interface{} {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		if len(t) <= max {
+			return t
+		}
+		n := max
+		for n > 0 && !utf8.RuneStart(t[n]) {
+			n--
+		}
+		return t[:n] + fmt.Sprintf("...[truncated %d bytes]", len(t)-n)
+	case []byte:
+		if len(t) <= max {
+			return t
+		}
+		truncated := append([]byte{}, t[:max]...)
+		return append(truncated, fmt.Sprintf("...[truncated %d bytes]", len(t)-max)...)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Len() <= max {
+			return v
+		}
+		truncated := make([]interface{}, max, max+1)
+		for i := 0; i < max; i++ {
+			truncated[i] = rv.Index(i).Interface()
+		}
+		return append(truncated, fmt.Sprintf("...[truncated %d elements]", rv.Len()-max))
+	case reflect.Map:
+		if rv.Len() <= max {
+			return v
+		}
+		keys := make([]string, 0, rv.Len())
+		values := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			keys = append(keys, key)
+			values[key] = iter.Value().Interface()
+		}
+		sort.Strings(keys)
+
+		truncated := make(map[string]interface{}, max+1)
+		for _, key := range keys[:max] {
+			truncated[key] = values[key]
+		}
+		truncated["..."] = fmt.Sprintf("[truncated %d elements]", rv.Len()-max)
+		return truncated
+	}
+
+	return v
+}
@@ -0,0 +1,63 @@
+package scientist
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{nil, nil},
+		{1, 1},
+		{"abc", "abc"},
+		{"abcdef", "abc...[truncated 3 bytes]"},
+		{"ab☃", "ab...[truncated 3 bytes]"},
+		{[]byte("abcdef"), []byte("abc...[truncated 3 bytes]")},
+		{[]int{1, 2, 3}, []int{1, 2, 3}},
+		{[]int{1, 2, 3, 4}, []interface{}{1, 2, 3, "...[truncated 1 elements]"}},
+		{map[int]bool{1: true, 2: true, 3: true, 4: true}, map[string]interface{}{
+			"1": true, "2": true, "3": true, "...": "[truncated 1 elements]",
+		}},
+	}
+
+	for _, test := range tests {
+		if actual := truncate(test.value, 3); !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("truncate(%#v): expected %#v, got %#v", test.value, test.expected, actual)
+		}
+	}
+}
+
+func TestPublishTruncated(t *testing.T) {
+	e := New("truncate")
+	e.TruncateValues(3)
+	e.Use(func() (interface{}, error) {
+		return "abcdef", nil
+	})
+	e.Try(func() (interface{}, error) {
+		return "abcdef", nil
+	})
+
+	published := false
+	e.Publish(func(r Result) error {
+		published = true
+
+		for _, o := range r.Observations {
+			if o.Value != "abc...[truncated 3 bytes]" {
+				t.Errorf("%q value not truncated: %v", o.Name, o.Value)
+			}
+		}
+
+		return nil
+	})
+
+	if v, _ := e.Run(); v != "abcdef" {
+		t.Errorf("Unexpected control value: %v", v)
+	}
+
+	if !published {
+		t.Errorf("results never published")
+	}
+}
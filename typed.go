@@ -0,0 +1,88 @@
+package scientist
+
+import "context"
+
+type TypedExperiment[T any] struct {
+	*Experiment
+}
+
+func NewTyped[T any](name string) *TypedExperiment[T] {
+	return &TypedExperiment[T]{New(name)}
+}
+
+func (e *TypedExperiment[T]) Use(fn func() (T, error)) {
+	e.Behavior(controlBehavior, fn)
+}
+
+func (e *TypedExperiment[T]) UseContext(fn func(ctx context.Context) (T, error)) {
+	e.BehaviorContext(controlBehavior, fn)
+}
+
+func (e *TypedExperiment[T]) Try(fn func() (T, error)) {
+	e.Behavior(candidateBehavior, fn)
+}
+
+func (e *TypedExperiment[T]) TryContext(fn func(ctx context.Context) (T, error)) {
+	e.BehaviorContext(candidateBehavior, fn)
+}
+
+func (e *TypedExperiment[T]) Behavior(name string, fn func() (T, error)) {
+	if fn == nil {
+		e.Experiment.Behavior(name, nil)
+		return
+	}
+
+	e.Experiment.Behavior(name, func() (interface{}, error) {
+		return fn()
+	})
+}
+
+func (e *TypedExperiment[T]) BehaviorContext(name string, fn func(ctx context.Context) (T, error)) {
+	if fn == nil {
+		e.Experiment.BehaviorContext(name, nil)
+		return
+	}
+
+	e.Experiment.BehaviorContext(name, func(ctx context.Context) (interface{}, error) {
+		return fn(ctx)
+	})
+}
+
+func (e *TypedExperiment[T]) Compare(fn func(control, candidate T) (bool, error)) {
+	if fn == nil {
+		e.Experiment.Compare(nil)
+		return
+	}
+
+	e.Experiment.Compare(func(control, candidate interface{}) (bool, error) {
+		return fn(typed[T](control), typed[T](candidate))
+	})
+}
+
+func (e *TypedExperiment[T]) Ignore(fn func(control, candidate T) (bool, error)) {
+	if fn == nil {
+		e.Experiment.Ignore(nil)
+		return
+	}
+
+	e.Experiment.Ignore(func(control, candidate interface{}) (bool, error) {
+		return fn(typed[T](control), typed[T](candidate))
+	})
+}
+
+func (e *TypedExperiment[T]) Run() (T, error) {
+	v, err := e.Experiment.Run()
+	return typed[T](v), err
+}
+
+func (e *TypedExperiment[T]) RunBehavior(name string) (T, error) {
+	v, err := e.Experiment.RunBehavior(name)
+	return typed[T](v), err
+}
+
+// typed returns the zero value for nil values, like those returned with
+// errors.
+func typed[T any](v interface{}) T {
+	t, _ := v.(T)
+	return t
+}
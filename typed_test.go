@@ -0,0 +1,82 @@
+package scientist
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTypedExperiment(t *testing.T) {
+	e := NewTyped[[]string]("typed")
+	e.Use(func() ([]string, error) {
+		return []string{"a", "b"}, nil
+	})
+	e.Try(func() ([]string, error) {
+		return []string{"b", "a"}, nil
+	})
+	e.Compare(func(control, candidate []string) (bool, error) {
+		return len(control) == len(candidate), nil
+	})
+
+	published := false
+	e.Publish(func(r Result) error {
+		published = true
+
+		if !r.IsMatched() {
+			t.Errorf("not matched")
+		}
+
+		return nil
+	})
+
+	v, err := e.Run()
+	if len(v) != 2 || v[0] != "a" {
+		t.Errorf("Unexpected control value: %v", v)
+	}
+
+	if err != nil {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+
+	if !published {
+		t.Errorf("expected Publish callback to run")
+	}
+}
+
+func TestTypedExperimentError(t *testing.T) {
+	e := NewTyped[int]("typed")
+	e.Use(func() (int, error) {
+		return 0, errors.New("control")
+	})
+	e.Try(func() (int, error) {
+		return 1, nil
+	})
+
+	v, err := e.Run()
+	if v != 0 {
+		t.Errorf("Unexpected control value: %d", v)
+	}
+
+	if err == nil || err.Error() != "control" {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+}
+
+func TestTypedExperimentMismatch(t *testing.T) {
+	e := NewTyped[int]("typed")
+	e.ErrorOnMismatches = true
+	e.Use(func() (int, error) {
+		return 1, nil
+	})
+	e.Try(func() (int, error) {
+		return 2, nil
+	})
+
+	v, err := e.Run()
+	if v != 0 {
+		t.Errorf("Unexpected control value: %d", v)
+	}
+
+	if _, ok := err.(MismatchError); !ok {
+		t.Errorf("Unexpected control error: %v", err)
+	}
+}
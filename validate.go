@@ -0,0 +1,166 @@
+package scientist
+
+import (
+	"fmt"
+	"reflect"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type ValidationError struct {
+	Experiment string
+	Errs       []error
+}
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("[scientist] experiment %q is invalid: %s", e.Experiment, strings.Join(msgs, "; "))
+}
+
+// validation collects configuration mistakes as the experiment is set up, so
+// they can be reported before it runs. The experiment is sealed by its first
+// run, since changing it afterwards would race with other runs.
+type validation struct {
+	sync.Mutex
+	sealed   int32
+	errs     []error
+	sealErrs []error
+
+	// names and labels are worked out once when the experiment is sealed,
+	// since its behaviors can't change after that.
+	names  []string
+	labels map[string]pprof.LabelSet
+
+	// controlErr is the validation of a run of the control, which most runs
+	// are.
+	controlErr error
+}
+
+func (e *Experiment) Validate() error {
+	e.validation.Lock()
+	errs := e.validation.errs
+	e.validation.Unlock()
+
+	return e.validate(controlBehavior, errs)
+}
+
+func (e *Experiment) validate(name string, errs []error) error {
+	if _, ok := e.behaviors[name]; !ok {
+		errs = append([]error{behaviorNotFound(e, name)}, errs...)
+	}
+
+	if p := e.promotion; p != nil {
+		if _, ok := e.behaviors[p.name]; !ok {
+			errs = append([]error{fmt.Errorf("promoted behavior %q not found", p.name)}, errs...)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return ValidationError{Experiment: e.Name, Errs: errs}
+}
+
+// seal stops the experiment from changing, and validates it for a run. Only
+// the mistakes made before the first run fail the run. Later changes are
+// ignored, and only reported.
+func (e *Experiment) seal(name string) error {
+	// sealErrs doesn't change once the experiment is sealed, so runs after the
+	// first one don't need the lock.
+	if atomic.LoadInt32(&e.validation.sealed) == 0 {
+		e.validation.Lock()
+		if e.validation.sealed == 0 {
+			e.validation.sealErrs = append([]error(nil), e.validation.errs...)
+			e.validation.names = sortedNames(e.behaviors)
+			e.validation.labels = make(map[string]pprof.LabelSet, len(e.behaviors))
+			for name := range e.behaviors {
+				e.validation.labels[name] = pprof.Labels("experiment", e.Name, "behavior", name)
+			}
+			e.validation.controlErr = e.validate(controlBehavior, e.validation.sealErrs)
+			atomic.StoreInt32(&e.validation.sealed, 1)
+		}
+		e.validation.Unlock()
+	}
+
+	if name == controlBehavior {
+		return e.validation.controlErr
+	}
+	return e.validate(name, e.validation.sealErrs)
+}
+
+// configurable records an error and returns false if the experiment can't be
+// changed with the given method.
+func (e *Experiment) configurable(method string) bool {
+	e.validation.Lock()
+	sealed := e.validation.sealed == 1
+	if sealed {
+		e.validation.errs = append(e.validation.errs, fmt.Errorf("%s called after the experiment ran", method))
+	}
+	e.validation.Unlock()
+
+	if sealed {
+		e.report(e.resultErr("configure", fmt.Errorf("[scientist] %s called on experiment %q after it ran", method, e.Name)))
+	}
+	return !sealed
+}
+
+func (e *Experiment) behavior(name string, fn interface{}) bool {
+	if !e.callback(fmt.Sprintf("Behavior(%q)", name), fn) {
+		return false
+	}
+
+	if _, ok := e.behaviors[name]; ok {
+		e.invalid(fmt.Errorf("behavior %q is already defined", name))
+		return false
+	}
+
+	return true
+}
+
+// callback is like configurable, and also rejects nil callbacks.
+func (e *Experiment) callback(method string, fn interface{}) bool {
+	if !e.configurable(method) {
+		return false
+	}
+
+	if isNil(fn) {
+		e.invalid(fmt.Errorf("%s called with a nil callback", method))
+		return false
+	}
+
+	return true
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	switch rv := reflect.ValueOf(v); rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+func (e *Experiment) invalid(err error) {
+	e.validation.Lock()
+	e.validation.errs = append(e.validation.errs, err)
+	e.validation.Unlock()
+}
+
+func sortedNames(behaviors map[string]behaviorFunc) []string {
+	names := make([]string, 0, len(behaviors))
+	for name := range behaviors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
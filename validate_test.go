@@ -0,0 +1,103 @@
+package scientist
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	e := New("validate")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+
+	if err := e.Validate(); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+
+	e.Try(func() (interface{}, error) {
+		return 3, nil
+	})
+	e.Compare(nil)
+
+	var verr ValidationError
+	if err := e.Validate(); !errors.As(err, &verr) {
+		t.Fatalf("Unexpected validation error: %v", err)
+	}
+
+	if len(verr.Errs) != 2 {
+		t.Errorf("Unexpected validation errors: %v", verr.Errs)
+	}
+
+	msg := verr.Error()
+	if !strings.Contains(msg, `behavior "candidate" is already defined`) || !strings.Contains(msg, "Compare called with a nil callback") {
+		t.Errorf("Unexpected validation message: %s", msg)
+	}
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	v, err := e.Run()
+	if v != nil || !errors.As(err, &verr) {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+
+	if len(reported) != 1 || reported[0].Operation != "validate" {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+}
+
+func TestValidateNoControl(t *testing.T) {
+	e := NewTyped[int]("validate")
+	e.Try(func() (int, error) {
+		return 2, nil
+	})
+
+	err := e.Validate()
+	if err == nil || !strings.Contains(err.Error(), `Behavior "control" not found`) {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+
+	e.Behavior("typed", nil)
+	if err := e.Validate(); err == nil || !strings.Contains(err.Error(), `Behavior("typed") called with a nil callback`) {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+}
+
+func TestSealedAfterRun(t *testing.T) {
+	e := basicExperiment()
+
+	var reported []ResultError
+	e.ReportErrors(func(errs ...ResultError) {
+		reported = append(reported, errs...)
+	})
+
+	if _, err := e.Run(); err != nil {
+		t.Fatalf("Unexpected control error: %v", err)
+	}
+
+	e.Compare(func(control, candidate interface{}) (bool, error) {
+		return true, nil
+	})
+
+	if len(reported) != 1 || reported[0].Operation != "configure" {
+		t.Errorf("Unexpected reported errors: %v", reported)
+	}
+
+	if err := e.Validate(); err == nil {
+		t.Errorf("expected a validation error for the late change")
+	}
+
+	r := Run(e, "control")
+	assertObservationNames(t, "mismatched", r.Mismatched, []string{"candidate", "three"})
+
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control result: %v, %v", v, err)
+	}
+}
@@ -0,0 +1,72 @@
+package scientist
+
+import "context"
+
+// Variant deterministically assigns a key to one of the experiment's
+// behaviors, control included, splitting keys evenly between them. Adding or
+// removing a behavior reassigns keys.
+func (e *Experiment) Variant(key string) string {
+	names := behaviorNames(e)
+	if len(names) == 0 {
+		return controlBehavior
+	}
+
+	// variants are hashed with their own salt, since RunPercent samples keys
+	// by their bucket, and every sampled key would get the same variant.
+	i := int(bucket(e.Name+"\x00variant", key) / 100 * float64(len(names)))
+	if i >= len(names) {
+		i = len(names) - 1
+	}
+	return names[i]
+}
+
+// RunVariant runs only the behavior the key is assigned to, for A/B tests
+// instead of shadowing. Its outcome is published as the Result's Control, with
+// the behavior's name in Variant. Disabled experiments, and keys that
+// RunPercent or RunIf skip, run the control like any other run.
+func (e *Experiment) RunVariant(key string) (interface{}, error) {
+	return e.RunVariantContext(context.Background(), key)
+}
+
+func (e *Experiment) RunVariantContext(ctx context.Context, key string) (interface{}, error) {
+	return e.run(controlBehavior, runOptions{ctx: ctx, key: key, keyed: true, variant: true})
+}
+
+// runVariant observes the variant like a candidate, so its panics are
+// recorded instead of raised. If it panics, or the circuit breaker or load
+// shedding skips it, the control runs in its place.
+func runVariant(e *Experiment, name string, opts runOptions) Result {
+	r := newResult(e, opts)
+	r.Variant = e.Variant(opts.key)
+	if r.Variant == name {
+		r.Control = observeControl(opts.ctx, e, name, 1)
+	} else {
+		o := observeCandidate(opts.ctx, e, r.Variant, e.behaviors[r.Variant])
+		recordBreakers(e, []*Observation{o})
+		if _, panicked := o.Err.(PanicError); !panicked && o.SkipReason == "" {
+			r.Control = o
+		} else {
+			r.Control = observeControl(opts.ctx, e, name, 1)
+			if o.SkipReason != "" {
+				r.Skipped = []*Observation{o}
+			} else {
+				r.Candidates = []*Observation{o}
+			}
+		}
+	}
+
+	if e.versions != nil {
+		r.Control.Version = e.versions[r.Control.Name]
+	}
+	r.Observations = append([]*Observation{r.Control}, r.Candidates...)
+	r = afterRun(r)
+	r = intercept(r)
+
+	e.state.record(r)
+	r = publish(r)
+	if len(r.Errors) > 0 {
+		e.report(r.Errors...)
+	}
+
+	return r
+}
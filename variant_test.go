@@ -0,0 +1,144 @@
+package scientist
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRunVariant(t *testing.T) {
+	ran := map[string]int{}
+	var published []Result
+	for i := 0; i < 300; i++ {
+		key := fmt.Sprint(i)
+		e := New("run-variant")
+		for _, name := range []string{"control", "a", "b"} {
+			name := name
+			e.Behavior(name, func() (interface{}, error) {
+				ran[name]++
+				return name, nil
+			})
+		}
+		e.Publish(func(r Result) error {
+			published = append(published, r)
+			return nil
+		})
+
+		variant := e.Variant(key)
+		if again := e.Variant(key); again != variant {
+			t.Fatalf("Expected key %q to get the same variant: %q, %q", key, variant, again)
+		}
+
+		v, err := e.RunVariant(key)
+		if v != variant || err != nil {
+			t.Fatalf("Unexpected result for variant %q: %v, %v", variant, v, err)
+		}
+	}
+
+	if len(published) != 300 {
+		t.Fatalf("Expected 300 published results, got %d", len(published))
+	}
+
+	for _, name := range []string{"control", "a", "b"} {
+		if ran[name] < 70 || ran[name] > 130 {
+			t.Errorf("Expected about 100 runs of %q, got %d", name, ran[name])
+		}
+	}
+
+	r := published[0]
+	if r.Variant == "" || r.Control.Name != r.Variant || len(r.Candidates) != 0 || r.IsMatched() {
+		t.Errorf("Unexpected variant result: %+v", r)
+	}
+
+	if p := r.Payload(); p.Variant != r.Variant || p.Matched {
+		t.Errorf("Unexpected variant payload: %+v", p)
+	}
+}
+
+func TestRunVariantDisabled(t *testing.T) {
+	e := New("run-variant-disabled")
+	e.Use(func() (interface{}, error) {
+		return "control", nil
+	})
+	e.Try(func() (interface{}, error) {
+		return "candidate", nil
+	})
+	e.Disable()
+	defer e.Enable()
+
+	for i := 0; i < 20; i++ {
+		if v, _ := e.RunVariant(fmt.Sprint(i)); v != "control" {
+			t.Fatalf("Expected a disabled experiment to run the control: %v", v)
+		}
+	}
+}
+
+func TestRunVariantPanic(t *testing.T) {
+	e := New(t.Name())
+	e.Use(func() (interface{}, error) {
+		return "control", nil
+	})
+	e.Try(func() (interface{}, error) {
+		panic("candidate")
+	})
+
+	var results []Result
+	e.AfterRun(func(r Result) error {
+		results = append(results, r)
+		return nil
+	})
+
+	key := ""
+	for i := 0; key == ""; i++ {
+		if k := fmt.Sprint(i); e.Variant(k) == "candidate" {
+			key = k
+		}
+	}
+
+	before := e.Stats()
+	v, err := e.RunVariant(key)
+	if v != "control" || err != nil {
+		t.Fatalf("Expected the control to run in place of a panicking variant: %v, %v", v, err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected AfterRun to see 1 result, got %d", len(results))
+	}
+
+	r := results[0]
+	if r.Variant != "candidate" || r.Control.Name != "control" || len(r.Candidates) != 1 {
+		t.Fatalf("Unexpected variant result: %+v", r)
+	}
+
+	if _, ok := r.Candidates[0].Err.(PanicError); !ok {
+		t.Errorf("Expected the variant's panic to be recorded: %v", r.Candidates[0].Err)
+	}
+
+	if s := e.Stats(); s.Runs-before.Runs != 1 || s.Matched != before.Matched {
+		t.Errorf("Unexpected stats: %+v", s)
+	}
+}
+
+func TestRunVariantPercent(t *testing.T) {
+	e := New(uniqueName("run-variant-percent"))
+	e.RunPercent(50)
+	e.Use(func() (interface{}, error) {
+		return "control", nil
+	})
+	e.Try(func() (interface{}, error) {
+		return "candidate", nil
+	})
+
+	variants := map[string]int{}
+	e.Publish(func(r Result) error {
+		variants[r.Variant]++
+		return nil
+	})
+
+	for i := 0; i < 1000; i++ {
+		e.RunVariant(fmt.Sprint(i))
+	}
+
+	if variants["control"] < 200 || variants["candidate"] < 200 {
+		t.Errorf("Expected sampled keys to be split between variants: %v", variants)
+	}
+}
@@ -0,0 +1,28 @@
+package scientist
+
+import "context"
+
+// Warmup runs every behavior n times before each run that compares them,
+// throwing away what they return, so connecting and filling caches don't
+// count against the first behavior to run. It's meant for offline runs like
+// RunBatch, since it multiplies the work each run does. Candidates are still
+// sandboxed.
+func (e *Experiment) Warmup(n int) {
+	if !e.configurable("Warmup") {
+		return
+	}
+
+	e.warmups = n
+}
+
+func warmUp(ctx context.Context, e *Experiment, name string) {
+	for i := 0; i < e.warmups; i++ {
+		for _, bname := range behaviorNames(e) {
+			b := e.behaviors[bname]
+			if bname != name && e.sandbox != nil {
+				b = sandboxed(e, bname, b)
+			}
+			observe(ctx, e, bname, b)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package scientist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWarmup(t *testing.T) {
+	calls := map[string]int{}
+	connected := map[string]bool{}
+	behavior := func(name string) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			calls[name]++
+			if !connected[name] {
+				connected[name] = true
+				time.Sleep(20 * time.Millisecond)
+			}
+			return 1, nil
+		}
+	}
+
+	e := New("warmup")
+	e.Warmup(2)
+	e.Use(behavior("control"))
+	e.Try(behavior("candidate"))
+
+	var published Result
+	e.Publish(func(r Result) error {
+		published = r
+		return nil
+	})
+	e.Run()
+
+	if calls["control"] != 3 || calls["candidate"] != 3 {
+		t.Errorf("Expected 2 warmups and a measured run: %v", calls)
+	}
+
+	for _, o := range published.Observations {
+		if o.Runtime >= 20*time.Millisecond {
+			t.Errorf("Expected %q's runtime to exclude its warmup: %v", o.Name, o.Runtime)
+		}
+	}
+}
+
+func TestWarmupSkipped(t *testing.T) {
+	calls := 0
+	e := New("warmup-skipped")
+	e.Warmup(2)
+	e.RunIf(func() (bool, error) {
+		return false, nil
+	})
+	e.Use(func() (interface{}, error) {
+		calls++
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Run()
+
+	if calls != 1 {
+		t.Errorf("Expected skipped runs not to warm up, got %d calls", calls)
+	}
+}
@@ -0,0 +1,90 @@
+// Package webhook publishes scientist results by POSTing them as JSON to a
+// URL. Failed requests are retried with exponential backoff.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"scientist"
+	"time"
+)
+
+type Publisher struct {
+	URL    string
+	Header http.Header
+
+	// Client sends the requests. It defaults to a client with a 5 second
+	// timeout, which is also used when Client is nil.
+	Client *http.Client
+
+	// Retries is the number of times a failed request is retried. Requests
+	// are retried after network errors, 429 responses, and 5xx responses.
+	Retries int
+
+	// Backoff is the delay before the first retry. It doubles after each
+	// retry.
+	Backoff time.Duration
+}
+
+// defaultClient sends the requests of publishers without a Client.
+var defaultClient = &http.Client{Timeout: 5 * time.Second}
+
+func New(url string) *Publisher {
+	return &Publisher{
+		URL:     url,
+		Header:  make(http.Header),
+		Client:  &http.Client{Timeout: 5 * time.Second},
+		Retries: 3,
+		Backoff: 100 * time.Millisecond,
+	}
+}
+
+func (p *Publisher) Publish(r scientist.Result) error {
+	body, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	backoff := p.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := p.post(body)
+		if err == nil || !retry || attempt >= p.Retries {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (p *Publisher) post(body []byte) (retry bool, err error) {
+	req, err := http.NewRequest("POST", p.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	for key, values := range p.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = defaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	res.Body.Close()
+
+	if res.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("[scientist] webhook %s returned %s", p.URL, res.Status)
+	return res.StatusCode == 429 || res.StatusCode >= 500, err
+}
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"scientist"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(503)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "token abc" {
+			t.Errorf("Unexpected Authorization header: %q", r.Header.Get("Authorization"))
+		}
+
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected Content-Type header: %q", r.Header.Get("Content-Type"))
+		}
+
+		var p scientist.ResultPayload
+		if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+			t.Error(err)
+		}
+
+		if p.Experiment != "webhook" || !p.Mismatched || p.Control.Value != float64(1) {
+			t.Errorf("Unexpected payload: %+v", p)
+		}
+	}))
+	defer server.Close()
+
+	p := New(server.URL)
+	p.Backoff = 0
+	p.Header.Set("Authorization", "token abc")
+
+	e := scientist.New("webhook")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 2, nil
+	})
+
+	if err := p.Publish(scientist.Run(e, "control")); err != nil {
+		t.Errorf("Unexpected publish error: %v", err)
+	}
+
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestPublishGivesUp(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(400)
+	}))
+	defer server.Close()
+
+	p := New(server.URL)
+	p.Backoff = 0
+
+	e := scientist.New("webhook")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	if err := p.Publish(scientist.Run(e, "control")); err == nil {
+		t.Errorf("Expected a publish error")
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected no retries for a 400, got %d requests", requests)
+	}
+}
+
+func TestPublishWithoutClient(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	p := &Publisher{URL: server.URL}
+
+	e := scientist.New("webhook")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+
+	if err := p.Publish(scientist.Run(e, "control")); err != nil {
+		t.Errorf("Unexpected publish error: %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d", requests)
+	}
+}
@@ -0,0 +1,29 @@
+package scientist
+
+import "context"
+
+// WrapBehaviors wraps every behavior, including the control, for
+// cross-cutting concerns like logging, metrics, or retries. The first wrapper
+// added is the outermost. Wrappers run inside the behavior's runtime and
+// trace, and panics in wrappers are recovered like panics in behaviors.
+//
+//	e.WrapBehaviors(func(name string, next func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error) {
+//	  return func(ctx context.Context) (interface{}, error) {
+//	    log.Printf("running %s", name)
+//	    return next(ctx)
+//	  }
+//	})
+func (e *Experiment) WrapBehaviors(fn func(name string, next func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error)) {
+	if !e.callback("WrapBehaviors", fn) {
+		return
+	}
+
+	e.wrappers = append(e.wrappers, fn)
+}
+
+func wrapped(e *Experiment, name string, b behaviorFunc) behaviorFunc {
+	for i := len(e.wrappers) - 1; i >= 0; i-- {
+		b = e.wrappers[i](name, b)
+	}
+	return b
+}
@@ -0,0 +1,106 @@
+package scientist
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWrapBehaviors(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	logCalls := func(prefix string) func(name string, next func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error) {
+		return func(name string, next func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error) {
+			return func(ctx context.Context) (interface{}, error) {
+				mu.Lock()
+				calls = append(calls, prefix+":"+name)
+				mu.Unlock()
+				return next(ctx)
+			}
+		}
+	}
+
+	e := New("wrap")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.WrapBehaviors(logCalls("outer"))
+	e.WrapBehaviors(logCalls("inner"))
+
+	if v, err := e.Run(); v != 1 || err != nil {
+		t.Errorf("Unexpected control: %v, %v", v, err)
+	}
+
+	sort.Strings(calls)
+	expected := "inner:candidate inner:control outer:candidate outer:control"
+	if actual := strings.Join(calls, " "); actual != expected {
+		t.Errorf("Unexpected calls: %s", actual)
+	}
+}
+
+func TestWrapBehaviorsOrder(t *testing.T) {
+	var calls []string
+	e := New("wrap-order")
+	e.Use(func() (interface{}, error) {
+		calls = append(calls, "control")
+		return 1, nil
+	})
+	for _, name := range []string{"outer", "inner"} {
+		name := name
+		e.WrapBehaviors(func(_ string, next func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error) {
+			return func(ctx context.Context) (interface{}, error) {
+				calls = append(calls, name)
+				return next(ctx)
+			}
+		})
+	}
+
+	// Without candidates, the control is still wrapped.
+	e.Run()
+
+	if actual := strings.Join(calls, " "); actual != "outer inner control" {
+		t.Errorf("Unexpected calls: %s", actual)
+	}
+}
+
+func TestWrapBehaviorsRetry(t *testing.T) {
+	attempts := 0
+	e := New("wrap-retry")
+	e.Use(func() (interface{}, error) {
+		return 1, nil
+	})
+	e.Try(func() (interface{}, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, errors.New("flaky")
+		}
+		return 1, nil
+	})
+	e.WrapBehaviors(func(name string, next func(ctx context.Context) (interface{}, error)) func(ctx context.Context) (interface{}, error) {
+		return func(ctx context.Context) (v interface{}, err error) {
+			for i := 0; i < 3; i++ {
+				if v, err = next(ctx); err == nil {
+					break
+				}
+			}
+			return v, err
+		}
+	})
+
+	var result Result
+	e.Publish(func(r Result) error {
+		result = r
+		return nil
+	})
+	e.Run()
+
+	if !result.IsMatched() || attempts != 3 {
+		t.Errorf("Expected candidate to match after 3 attempts, got %d: %v", attempts, result.Candidates[0].Err)
+	}
+}